@@ -7,3 +7,15 @@ var GitignorePairs []byte
 
 //go:embed doc_pairs.json
 var DocPairs []byte
+
+//go:embed commit_types.json
+var CommitTypes []byte
+
+//go:embed versions.json
+var Versions []byte
+
+// GitignoreBundle is the gzipped JSON object mapping every github/gitignore
+// template path to its content, written by 'make collect'.
+//
+//go:embed gitignore_bundle.json.gz
+var GitignoreBundle []byte
@@ -0,0 +1,299 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/CrossEvol/setup/common"
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+// changesetsCmd represents the changesets command
+var changesetsCmd = &cobra.Command{
+	Use:   "changesets",
+	Short: "Set up Changesets for versioning monorepos",
+	Long: `This command sets up Changesets for your project.
+
+It detects your package manager, installs @changesets/cli, writes .changeset/config.json
+with the access, base branch and linked/fixed package groups you choose from the
+workspace packages, and adds 'changeset', 'version-packages' and 'changeset:publish'
+scripts to package.json, leaving 'release' to release-it. It can also generate a
+GitHub Actions release workflow.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setupChangesets()
+	},
+}
+
+// changesetConfig mirrors .changeset/config.json, in the order written by 'changeset init'.
+type changesetConfig struct {
+	Schema                     string     `json:"$schema"`
+	Changelog                  string     `json:"changelog"`
+	Commit                     bool       `json:"commit"`
+	Fixed                      [][]string `json:"fixed"`
+	Linked                     [][]string `json:"linked"`
+	Access                     string     `json:"access"`
+	BaseBranch                 string     `json:"baseBranch"`
+	UpdateInternalDependencies string     `json:"updateInternalDependencies"`
+	Ignore                     []string   `json:"ignore"`
+}
+
+// defaultBaseBranch asks git for the remote default branch, falling back to "main".
+func defaultBaseBranch() string {
+	out, err := exec.Command("git", "symbolic-ref", "--short", "refs/remotes/origin/HEAD").Output()
+	if err == nil {
+		branch := strings.TrimSpace(string(out))
+		if i := strings.Index(branch, "/"); i != -1 {
+			return branch[i+1:]
+		}
+	}
+	return "main"
+}
+
+// usesGitHub reports whether the project already has GitHub Actions or a GitHub remote.
+func usesGitHub() bool {
+	if _, err := os.Stat(filepath.Join(".github", "workflows")); err == nil {
+		return true
+	}
+	out, err := exec.Command("git", "remote", "get-url", "origin").Output()
+	return err == nil && strings.Contains(string(out), "github.com")
+}
+
+// changesetDir holds the config and the pending changesets.
+const changesetDir = ".changeset"
+
+// changesetReadme is the README.md written by 'changeset init'.
+const changesetReadme = `# Changesets
+
+Hello and welcome! This folder has been automatically generated by ` + "`@changesets/cli`" + `, a build tool that works
+with multi-package repos, or single-package repos to help you version and publish your code. You can
+find the full documentation for it [in our repository](https://github.com/changesets/changesets)
+
+We have a quick list of common questions to get you started engaging with this project in
+[our documentation](https://github.com/changesets/changesets/blob/main/docs/common-questions.md)
+`
+
+// changesetsInstalls lists what setup changesets installs, into the workspace root.
+func changesetsInstalls(workspacePlan) []installRequest {
+	return []installRequest{{What: "@changesets/cli", Packages: []string{"@changesets/cli"}}}
+}
+
+func setupChangesets() error {
+	currentTool = CHANGESETS
+	reportInfo("Setting up changesets...")
+
+	pm, found := detectPackageManager()
+	if !found {
+		return errNoPackageManager
+	}
+	foundPackageManager := pm.Name
+	runCmdPrefix := pm.RunCmd
+
+	// 1. Ask for the release settings
+	packages := common.WorkspacePackages()
+	reportInfo("Found %d workspace package(s).", len(packages))
+
+	access := "restricted"
+	for _, pkg := range packages {
+		if !pkg.Private && strings.HasPrefix(pkg.Name, "@") {
+			// Scoped packages are private on the registry unless published as public
+			access = "public"
+			break
+		}
+	}
+	baseBranch := defaultBaseBranch()
+	var fixed, linked []string
+	generateWorkflow := usesGitHub()
+
+	var packageOptions []huh.Option[string]
+	for _, pkg := range packages {
+		packageOptions = append(packageOptions, huh.NewOption(fmt.Sprintf("%s (%s)", pkg.Name, pkg.Dir), pkg.Name))
+	}
+
+	groups := []*huh.Group{
+		huh.NewGroup(
+			huh.NewSelect[string]().Title("Publish access").
+				Options(
+					huh.NewOption("restricted", "restricted"),
+					huh.NewOption("public", "public"),
+				).
+				Description("Scoped packages must be published as public to be visible on npm").
+				Value(&access),
+			huh.NewInput().Title("Base branch").
+				Description("The branch changesets compares against").
+				Value(&baseBranch),
+		),
+	}
+	if len(packageOptions) > 1 {
+		groups = append(groups, huh.NewGroup(
+			huh.NewMultiSelect[string]().Title("Fixed group").
+				Options(packageOptions...).
+				Description("Packages that are always released together with the same version").
+				Value(&fixed),
+			huh.NewMultiSelect[string]().Title("Linked group").
+				Options(packageOptions...).
+				Description("Packages that share the highest version when released together").
+				Value(&linked),
+		))
+	}
+	groups = append(groups, huh.NewGroup(
+		huh.NewConfirm().Title("Generate a GitHub Actions release workflow?").
+			Value(&generateWorkflow),
+	))
+
+	err := huh.NewForm(groups...).Run()
+	if err != nil {
+		return err
+	}
+
+	// 2. Install @changesets/cli into the workspace root
+	var errs []error
+	err = runInstalls(pm, changesetsInstalls(workspacePlan{}))
+	if err != nil {
+		errs = append(errs, err)
+		reportWarning("Attempting to continue assuming packages are already installed.")
+	}
+
+	// 3. Create .changeset/config.json and README.md
+	config := changesetConfig{
+		Schema:                     "https://unpkg.com/@changesets/config@3.0.0/schema.json",
+		Changelog:                  "@changesets/cli/changelog",
+		Commit:                     false,
+		Fixed:                      [][]string{},
+		Linked:                     [][]string{},
+		Access:                     access,
+		BaseBranch:                 strings.TrimSpace(baseBranch),
+		UpdateInternalDependencies: "patch",
+		Ignore:                     []string{},
+	}
+	// A single package cannot form a group, and a package cannot be both fixed and linked
+	if len(fixed) > 1 {
+		config.Fixed = append(config.Fixed, fixed)
+		linked = slices.DeleteFunc(linked, func(name string) bool {
+			return slices.Contains(fixed, name)
+		})
+	}
+	if len(linked) > 1 {
+		config.Linked = append(config.Linked, linked)
+	}
+
+	configPath := filepath.Join(changesetDir, "config.json")
+	err = os.MkdirAll(changesetDir, 0755)
+	if err != nil {
+		reportError("creating directory %s: %v", changesetDir, err)
+		errs = append(errs, &configError{Path: changesetDir, Err: err})
+	} else {
+		configData, _ := json.MarshalIndent(config, "", "  ")
+		errs = append(errs, writeFile(configPath, append(configData, '\n'), 0644))
+
+		readmePath := filepath.Join(changesetDir, "README.md")
+		if _, statErr := os.Stat(readmePath); os.IsNotExist(statErr) {
+			errs = append(errs, writeFile(readmePath, []byte(changesetReadme), 0644))
+		} else {
+			reportFileSkipped(readmePath, "already exists")
+		}
+	}
+
+	// 4. Add the changeset scripts to package.json, apart from the release
+	// script of release-it
+	errs = append(errs, setPackageScripts(".", map[string]string{
+		"changeset":         "changeset",
+		"version-packages":  "changeset version",
+		"changeset:publish": "changeset publish",
+	}))
+
+	// 5. Generate the GitHub Actions workflow
+	if generateWorkflow {
+		errs = append(errs, writeChangesetsWorkflow(foundPackageManager, runCmdPrefix, config.BaseBranch))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	reportInfo("changesets setup complete.")
+	return nil
+}
+
+// writeChangesetsWorkflow creates .github/workflows/release.yml driven by changesets/action.
+func writeChangesetsWorkflow(packageManager, runCmdPrefix, baseBranch string) error {
+	const workflowTemplate = `name: Release
+
+on:
+  push:
+    branches:
+      - %s
+
+concurrency: ${{ github.workflow }}-${{ github.ref }}
+
+permissions:
+  contents: write
+  pull-requests: write
+
+jobs:
+  release:
+    name: Release
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+%s
+      - uses: actions/setup-node@v4
+        with:
+          node-version: 20%s
+
+      - name: Install dependencies
+        run: %s
+
+      - name: Create Release Pull Request or Publish
+        uses: changesets/action@v1
+        with:
+          version: %s version-packages
+          publish: %s changeset:publish
+        env:
+          GITHUB_TOKEN: ${{ secrets.GITHUB_TOKEN }}
+          NPM_TOKEN: ${{ secrets.NPM_TOKEN }}
+`
+	setupSteps := ""
+	installStep := "npm ci"
+	cache := "\n          cache: " + packageManager
+	switch packageManager {
+	case "pnpm":
+		setupSteps = "\n      - uses: pnpm/action-setup@v4\n"
+		installStep = "pnpm install --frozen-lockfile"
+	case "yarn":
+		installStep = "yarn install --frozen-lockfile"
+	case "bun":
+		setupSteps = "\n      - uses: oven-sh/setup-bun@v2\n"
+		installStep = "bun install --frozen-lockfile"
+		// actions/setup-node has no bun cache, and no npm cache without package-lock.json
+		cache = ""
+	}
+
+	workflowDir := filepath.Join(".github", "workflows")
+	workflowPath := filepath.Join(workflowDir, "release.yml")
+	if _, err := os.Stat(workflowPath); err == nil {
+		reportFileSkipped(workflowPath, "already exists, skipping workflow generation")
+		return nil
+	}
+
+	err := os.MkdirAll(workflowDir, 0755)
+	if err != nil {
+		reportError("creating directory %s: %v", workflowDir, err)
+		return &configError{Path: workflowDir, Err: err}
+	}
+
+	content := fmt.Sprintf(workflowTemplate, baseBranch, setupSteps, cache, installStep, runCmdPrefix, runCmdPrefix)
+	return writeFile(workflowPath, []byte(content), 0644)
+}
+
+func init() {
+	rootCmd.AddCommand(changesetsCmd)
+}
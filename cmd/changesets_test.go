@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteChangesetsWorkflow(t *testing.T) {
+	tests := []struct {
+		packageManager, runCmdPrefix string
+		want, unwanted               []string
+	}{
+		{"npm", "npm run", []string{"cache: npm", "run: npm ci", "publish: npm run changeset:publish"}, nil},
+		{"pnpm", "pnpm", []string{"pnpm/action-setup@v4", "cache: pnpm", "publish: pnpm changeset:publish"}, nil},
+		{"yarn", "yarn", []string{"cache: yarn", "run: yarn install --frozen-lockfile"}, nil},
+		{"bun", "bun run", []string{"oven-sh/setup-bun@v2", "run: bun install --frozen-lockfile"}, []string{"cache:"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.packageManager, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			if err := writeChangesetsWorkflow(tt.packageManager, tt.runCmdPrefix, "main"); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(filepath.Join(".github", "workflows", "release.yml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			workflow := string(data)
+			for _, want := range append(tt.want, "- main", "version: "+tt.runCmdPrefix+" version-packages") {
+				if !strings.Contains(workflow, want) {
+					t.Errorf("workflow is missing %q:\n%s", want, workflow)
+				}
+			}
+			for _, unwanted := range append(tt.unwanted, " release\n") {
+				if strings.Contains(workflow, unwanted) {
+					t.Errorf("workflow contains %q:\n%s", unwanted, workflow)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/CrossEvol/setup/common"
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+// commitCmd represents the commit command
+var commitCmd = &cobra.Command{
+	Use:   "commit",
+	Short: "Compose a conventional commit message interactively",
+	Long: `Compose a conventional commit message and run 'git commit' with it.
+
+The type is chosen from the 'type-enum' rule of commitlint.config.cjs, and the
+message is validated against the same rules the config declares, so the
+commit-msg hook will accept it. Without a config the rules generated by
+'setup commitlint' are used.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		all, _ := cmd.Flags().GetBool("all")
+		return composeCommit(dryRun, all)
+	},
+}
+
+// projectCommitlintRules reads the rules of commitlint.config.cjs, falling back
+// to the rules 'setup commitlint' would generate.
+func projectCommitlintRules() common.Rules {
+	content, err := os.ReadFile(commitlintConfigFile)
+	if err != nil {
+		return common.ParseCommitlintRules(renderCommitlintConfig(common.DefaultCommitTypes()))
+	}
+	return common.ParseCommitlintRules(string(content))
+}
+
+// problemsError joins the error-level problems into one error.
+func problemsError(problems []common.Problem) error {
+	var messages []string
+	for _, problem := range problems {
+		if problem.Level == common.LevelError {
+			messages = append(messages, fmt.Sprintf("%s [%s]", problem.Message, problem.Name))
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(messages, "; "))
+}
+
+func composeCommit(dryRun, all bool) error {
+	rules := projectCommitlintRules()
+	maxLength := 0
+	if rule, ok := rules.Enabled("header-max-length"); ok {
+		maxLength = rule.IntValue(100)
+	}
+
+	// Offer the allowed types, keeping the descriptions of the project or catalog
+	types := projectCommitTypes()
+	if rule, ok := rules.Enabled("type-enum"); ok && rule.When != "never" {
+		var allowed []common.CommitType
+		for _, name := range rule.ListValue() {
+			t, _ := common.FindCommitType(types, name)
+			t.Name = name
+			allowed = append(allowed, t)
+		}
+		types = allowed
+	}
+	var typeOptions []huh.Option[string]
+	for _, t := range types {
+		label := t.Name
+		if t.Description != "" {
+			label = fmt.Sprintf("%-10s %s", t.Name+":", t.Description)
+		}
+		typeOptions = append(typeOptions, huh.NewOption(label, t.Name))
+	}
+
+	var commitType, scope, subject, body, breakingDescription, issues string
+	var breaking bool
+
+	header := func() string {
+		return common.FormatHeader(commitType, strings.TrimSpace(scope), breaking, strings.TrimSpace(subject))
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().Title("Type").
+				Options(typeOptions...).
+				Description("Select the type of change you're committing").
+				Value(&commitType),
+			huh.NewInput().Title("Scope").
+				Description("Optional: the part of the codebase this change affects").
+				Value(&scope).
+				Validate(func(s string) error {
+					return problemsError(common.LintHeader(rules, commitType, strings.TrimSpace(s), "-", "-"))
+				}),
+			huh.NewConfirm().Title("Is this a breaking change?").
+				Value(&breaking),
+		),
+		huh.NewGroup(
+			huh.NewInput().Title("Subject").
+				DescriptionFunc(func() string {
+					length := len([]rune(header()))
+					if maxLength == 0 {
+						return fmt.Sprintf("%s (%d characters)", header(), length)
+					}
+					if length > maxLength {
+						return fmt.Sprintf("%s (%d/%d, %d too long)", header(), length, maxLength, length-maxLength)
+					}
+					return fmt.Sprintf("%s (%d/%d)", header(), length, maxLength)
+				}, &subject).
+				Value(&subject).
+				Validate(func(s string) error {
+					s = strings.TrimSpace(s)
+					return problemsError(common.LintHeader(rules, commitType, strings.TrimSpace(scope), s,
+						common.FormatHeader(commitType, strings.TrimSpace(scope), breaking, s)))
+				}),
+			huh.NewText().Title("Body").
+				Description("Optional: a longer description of the change").
+				Value(&body),
+		),
+		huh.NewGroup(
+			huh.NewInput().Title("Breaking change").
+				Description("Describe the breaking change and the migration path").
+				Value(&breakingDescription).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return errors.New("a breaking change needs a description")
+					}
+					return nil
+				}),
+		).WithHideFunc(func() bool { return !breaking }),
+		huh.NewGroup(
+			huh.NewInput().Title("Issues").
+				Description("Optional: issue references, e.g. 'Closes #12' or 'Refs #34, #35'").
+				Value(&issues),
+		),
+	)
+
+	err := form.Run()
+	if err != nil {
+		return err
+	}
+
+	message := buildCommitMessage(header(), body, breaking, breakingDescription, issues)
+	if !checkCommitMessage(message) {
+		fmt.Println("Your message was:")
+		fmt.Println(message)
+		return errCommitMessage
+	}
+
+	if dryRun {
+		fmt.Println(message)
+		return nil
+	}
+
+	gitArgs := []string{"commit", "-F", "-"}
+	if all {
+		gitArgs = append(gitArgs, "--all")
+	}
+	gitCmd := exec.Command("git", gitArgs...)
+	gitCmd.Stdin = strings.NewReader(message)
+	gitCmd.Stdout = os.Stdout
+	gitCmd.Stderr = os.Stderr
+	err = gitCmd.Run()
+	if err != nil {
+		fmt.Println("Your message was:")
+		fmt.Println(message)
+		return fmt.Errorf("running git commit: %w", err)
+	}
+	return nil
+}
+
+// buildCommitMessage joins header, body and footers with blank lines.
+func buildCommitMessage(header, body string, breaking bool, breakingDescription, issues string) string {
+	parts := []string{header}
+	if body = strings.TrimSpace(body); body != "" {
+		parts = append(parts, body)
+	}
+
+	var footers []string
+	if breaking && strings.TrimSpace(breakingDescription) != "" {
+		footers = append(footers, "BREAKING CHANGE: "+strings.TrimSpace(breakingDescription))
+	}
+	if issues = strings.TrimSpace(issues); issues != "" {
+		footers = append(footers, issues)
+	}
+	if len(footers) > 0 {
+		parts = append(parts, strings.Join(footers, "\n"))
+	}
+
+	return strings.Join(parts, "\n\n") + "\n"
+}
+
+func init() {
+	rootCmd.AddCommand(commitCmd)
+
+	commitCmd.Flags().Bool("dry-run", false, "Print the message instead of committing")
+	commitCmd.Flags().BoolP("all", "a", false, "Stage modified and deleted files before committing")
+}
@@ -0,0 +1,439 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+
+	"github.com/CrossEvol/setup/common"
+	"github.com/spf13/cobra"
+)
+
+const commitlintConfigFile = `commitlint.config.cjs`
+const releaseItConfigFile = `.release-it.json`
+
+// commitTypeName matches the type names that commitlint and release-it accept.
+var commitTypeName = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// commitTypesCmd represents the commit-types command
+var commitTypesCmd = &cobra.Command{
+	Use:   "commit-types",
+	Short: "Manage the conventional commit types of the project",
+	Long: `Manage the conventional commit types shared by commitlint and release-it.
+
+The types allowed by the 'type-enum' rule of commitlint.config.cjs and the changelog
+sections of .release-it.json are kept in sync: adding or removing a type updates
+both generated files in one go.`,
+}
+
+var commitTypesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the commit types of the project",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listCommitTypes()
+	},
+}
+
+var commitTypesAddCmd = &cobra.Command{
+	Use:   "add <type>",
+	Short: "Allow a commit type and give it a changelog section",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !commitTypeName.MatchString(args[0]) {
+			return fmt.Errorf("invalid commit type %q: use lowercase letters, digits and '-'", args[0])
+		}
+		commitType, _ := common.FindCommitType(common.DefaultCommitTypes(), args[0])
+		commitType.Name = args[0]
+		if cmd.Flags().Changed("description") {
+			commitType.Description, _ = cmd.Flags().GetString("description")
+		}
+		if cmd.Flags().Changed("section") {
+			commitType.Section.En, _ = cmd.Flags().GetString("section")
+		}
+		if cmd.Flags().Changed("section-zh") {
+			commitType.Section.Zh, _ = cmd.Flags().GetString("section-zh")
+		}
+		if cmd.Flags().Changed("emoji") {
+			commitType.Section.Emoji, _ = cmd.Flags().GetString("emoji")
+		}
+		if cmd.Flags().Changed("hidden") {
+			commitType.Hidden, _ = cmd.Flags().GetBool("hidden")
+		}
+		if commitType.Section.En == "" && commitType.Section.Zh == "" {
+			commitType.Section.En = strings.ToUpper(commitType.Name[:1]) + commitType.Name[1:]
+		}
+		return addCommitType(commitType)
+	},
+}
+
+var commitTypesRemoveCmd = &cobra.Command{
+	Use:   "remove <type>",
+	Short: "Disallow a commit type and drop its changelog section",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return removeCommitType(args[0])
+	},
+}
+
+// projectCommitTypes returns the types of commitlint.config.cjs, completed with
+// the changelog sections of .release-it.json. The embedded catalog is used when
+// neither file exists.
+func projectCommitTypes() []common.CommitType {
+	catalog := common.DefaultCommitTypes()
+	var types []common.CommitType
+
+	content, err := os.ReadFile(commitlintConfigFile)
+	hasCommitlint := err == nil
+	if hasCommitlint {
+		types, _ = common.ParseTypeEnum(string(content))
+	}
+
+	sections := make(map[string]releaseItPresetType)
+	if _, presetTypes, err := readReleaseItTypes(); err == nil {
+		for _, entry := range presetTypes {
+			sections[entry.Type] = entry
+			if !hasCommitlint {
+				types = append(types, common.CommitType{Name: entry.Type})
+			}
+		}
+	}
+
+	if types == nil {
+		return catalog
+	}
+
+	for i, t := range types {
+		known, _ := common.FindCommitType(catalog, t.Name)
+		if t.Description == "" {
+			types[i].Description = known.Description
+		}
+		if entry, ok := sections[t.Name]; ok {
+			types[i].Section = common.ChangelogSection{En: entry.Section}
+			types[i].Hidden = entry.Hidden
+		} else {
+			types[i].Section = known.Section
+		}
+	}
+	return types
+}
+
+// releaseItTypesPath leads to the types of the conventional-changelog preset.
+var releaseItTypesPath = []string{"plugins", "@release-it/conventional-changelog", "preset", "types"}
+
+// readReleaseItTypes returns the content of .release-it.json and the types of
+// its conventional-changelog preset.
+func readReleaseItTypes() ([]byte, []releaseItPresetType, error) {
+	data, err := os.ReadFile(releaseItConfigFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	value := json.RawMessage(data)
+	for i, key := range releaseItTypesPath {
+		var object jsonObject
+		if err := json.Unmarshal(value, &object); err != nil {
+			return nil, nil, err
+		}
+		field, ok := object.get(key)
+		if !ok && i < len(releaseItTypesPath)-1 {
+			return nil, nil, fmt.Errorf("no @release-it/conventional-changelog preset in %s", releaseItConfigFile)
+		}
+		value = field
+	}
+
+	var types []releaseItPresetType
+	if value != nil {
+		if err := json.Unmarshal(value, &types); err != nil {
+			return nil, nil, err
+		}
+	}
+	return data, types, nil
+}
+
+// writeReleaseItTypes stores the preset types back into .release-it.json,
+// keeping the order of the keys the user wrote.
+func writeReleaseItTypes(data []byte, types []releaseItPresetType) error {
+	value, err := marshalReleaseItConfig(types)
+	if err != nil {
+		return err
+	}
+	config, err := replaceJSONPath(data, releaseItTypesPath, value)
+	if err != nil {
+		return err
+	}
+	data, err = marshalReleaseItConfig(config)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(releaseItConfigFile, data, 0644)
+}
+
+// replaceJSONPath sets the value at path in the JSON object data, leaving the
+// rest of it untouched.
+func replaceJSONPath(data json.RawMessage, path []string, value json.RawMessage) (json.RawMessage, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	var object jsonObject
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	field, ok := object.get(path[0])
+	if !ok && len(path) > 1 {
+		return nil, fmt.Errorf("no %s in %s", path[0], releaseItConfigFile)
+	}
+	field, err := replaceJSONPath(field, path[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	return object.set(path[0], field).MarshalJSON()
+}
+
+// jsonObject is a JSON object that keeps the order of its keys.
+type jsonObject []jsonField
+
+type jsonField struct {
+	Key   string
+	Value json.RawMessage
+}
+
+func (o *jsonObject) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil {
+		return err
+	} else if token != json.Delim('{') {
+		return fmt.Errorf("expected a JSON object, got %v", token)
+	}
+	*o = nil
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		*o = append(*o, jsonField{Key: token.(string), Value: value})
+	}
+	_, err := decoder.Token()
+	return err
+}
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	buf.WriteByte('{')
+	for i, field := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := encoder.Encode(field.Key); err != nil {
+			return nil, err
+		}
+		buf.Truncate(buf.Len() - 1) // Encode ends with a newline
+		buf.WriteByte(':')
+		buf.Write(field.Value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (o jsonObject) get(key string) (json.RawMessage, bool) {
+	for _, field := range o {
+		if field.Key == key {
+			return field.Value, true
+		}
+	}
+	return nil, false
+}
+
+// set replaces the value of key, or appends the key when it is missing.
+func (o jsonObject) set(key string, value json.RawMessage) jsonObject {
+	for i, field := range o {
+		if field.Key == key {
+			o[i].Value = value
+			return o
+		}
+	}
+	return append(o, jsonField{Key: key, Value: value})
+}
+
+// sectionStyle infers the language and emoji usage of existing changelog headings.
+func sectionStyle(types []releaseItPresetType) (string, bool) {
+	if len(types) == 0 {
+		return changelogBilingual, true
+	}
+	section := types[0].Section
+	emoji := false
+	for _, r := range section {
+		emoji = !unicode.IsLetter(r) && !unicode.IsDigit(r) && r > unicode.MaxASCII
+		break
+	}
+	switch {
+	case strings.Contains(section, " | "):
+		return changelogBilingual, emoji
+	case strings.IndexFunc(section, func(r rune) bool { return unicode.Is(unicode.Han, r) }) != -1:
+		return changelogChinese, emoji
+	default:
+		return changelogEnglish, emoji
+	}
+}
+
+func listCommitTypes() error {
+	types := projectCommitTypes()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tSECTION\tHIDDEN\tDESCRIPTION")
+	for _, t := range types {
+		section := changelogSection(t.Section, changelogBilingual, true)
+		fmt.Fprintf(w, "%s\t%s\t%t\t%s\n", t.Name, section, t.Hidden, t.Description)
+	}
+	return w.Flush()
+}
+
+func addCommitType(commitType common.CommitType) error {
+	var errs []error
+	updated := false
+
+	// 1. Allow the type in commitlint.config.cjs
+	content, err := os.ReadFile(commitlintConfigFile)
+	if err == nil {
+		types, ok := common.ParseTypeEnum(string(content))
+		if !ok {
+			reportFileSkipped(commitlintConfigFile, "no 'type-enum' rule found")
+		} else if _, found := common.FindCommitType(types, commitType.Name); found {
+			reportFileSkipped(commitlintConfigFile, fmt.Sprintf("type '%s' is already allowed", commitType.Name))
+		} else {
+			types = append(types, commitType)
+			newContent, _ := common.ReplaceTypeEnum(string(content), types)
+			err = os.WriteFile(commitlintConfigFile, []byte(newContent), 0644)
+			if err != nil {
+				reportError("writing %s: %v", commitlintConfigFile, err)
+				errs = append(errs, &configError{Path: commitlintConfigFile, Err: err})
+			} else {
+				emit(event{Type: eventFileWritten, Path: commitlintConfigFile, Value: commitType.Name, Message: fmt.Sprintf("Type '%s' added to %s.", commitType.Name, commitlintConfigFile)})
+				updated = true
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		reportError("reading %s: %v", commitlintConfigFile, err)
+		errs = append(errs, &configError{Path: commitlintConfigFile, Err: err})
+	}
+
+	// 2. Add the changelog section to .release-it.json
+	config, presetTypes, err := readReleaseItTypes()
+	if err == nil {
+		found := false
+		for _, entry := range presetTypes {
+			found = found || entry.Type == commitType.Name
+		}
+		if found {
+			reportFileSkipped(releaseItConfigFile, fmt.Sprintf("type '%s' already has a section", commitType.Name))
+		} else {
+			language, emoji := sectionStyle(presetTypes)
+			presetTypes = append(presetTypes, releaseItPresetType{
+				Type:    commitType.Name,
+				Section: changelogSection(commitType.Section, language, emoji),
+				Hidden:  commitType.Hidden,
+			})
+			err = writeReleaseItTypes(config, presetTypes)
+			if err != nil {
+				reportError("writing %s: %v", releaseItConfigFile, err)
+				errs = append(errs, &configError{Path: releaseItConfigFile, Err: err})
+			} else {
+				emit(event{Type: eventFileWritten, Path: releaseItConfigFile, Value: commitType.Name, Message: fmt.Sprintf("Type '%s' added to %s.", commitType.Name, releaseItConfigFile)})
+				updated = true
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		reportError("reading %s: %v", releaseItConfigFile, err)
+		errs = append(errs, &configError{Path: releaseItConfigFile, Err: err})
+	}
+
+	if !updated && len(errs) == 0 {
+		reportInfo("Nothing to do!")
+	}
+	return errors.Join(errs...)
+}
+
+func removeCommitType(name string) error {
+	var errs []error
+	updated := false
+
+	// 1. Disallow the type in commitlint.config.cjs
+	content, err := os.ReadFile(commitlintConfigFile)
+	if err == nil {
+		types, ok := common.ParseTypeEnum(string(content))
+		var kept []common.CommitType
+		for _, t := range types {
+			if t.Name != name {
+				kept = append(kept, t)
+			}
+		}
+		if ok && len(kept) != len(types) {
+			newContent, _ := common.ReplaceTypeEnum(string(content), kept)
+			err = os.WriteFile(commitlintConfigFile, []byte(newContent), 0644)
+			if err != nil {
+				reportError("writing %s: %v", commitlintConfigFile, err)
+				errs = append(errs, &configError{Path: commitlintConfigFile, Err: err})
+			} else {
+				emit(event{Type: eventFileWritten, Path: commitlintConfigFile, Value: name, Message: fmt.Sprintf("Type '%s' removed from %s.", name, commitlintConfigFile)})
+				updated = true
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		reportError("reading %s: %v", commitlintConfigFile, err)
+		errs = append(errs, &configError{Path: commitlintConfigFile, Err: err})
+	}
+
+	// 2. Drop the changelog section from .release-it.json
+	config, presetTypes, err := readReleaseItTypes()
+	if err == nil {
+		var kept []releaseItPresetType
+		for _, entry := range presetTypes {
+			if entry.Type != name {
+				kept = append(kept, entry)
+			}
+		}
+		if len(kept) != len(presetTypes) {
+			err = writeReleaseItTypes(config, kept)
+			if err != nil {
+				reportError("writing %s: %v", releaseItConfigFile, err)
+				errs = append(errs, &configError{Path: releaseItConfigFile, Err: err})
+			} else {
+				emit(event{Type: eventFileWritten, Path: releaseItConfigFile, Value: name, Message: fmt.Sprintf("Type '%s' removed from %s.", name, releaseItConfigFile)})
+				updated = true
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		reportError("reading %s: %v", releaseItConfigFile, err)
+		errs = append(errs, &configError{Path: releaseItConfigFile, Err: err})
+	}
+
+	if !updated && len(errs) == 0 {
+		reportInfo("Type '%s' not found. Nothing to do!", name)
+	}
+	return errors.Join(errs...)
+}
+
+func init() {
+	rootCmd.AddCommand(commitTypesCmd)
+	commitTypesCmd.AddCommand(commitTypesListCmd, commitTypesAddCmd, commitTypesRemoveCmd)
+
+	commitTypesAddCmd.Flags().String("description", "", "Description shown next to the type")
+	commitTypesAddCmd.Flags().String("section", "", "English changelog section heading")
+	commitTypesAddCmd.Flags().String("section-zh", "", "Chinese changelog section heading")
+	commitTypesAddCmd.Flags().String("emoji", "", "Emoji prefixed to the changelog section")
+	commitTypesAddCmd.Flags().Bool("hidden", false, "Hide the type from the changelog")
+}
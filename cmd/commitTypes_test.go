@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/CrossEvol/setup/common"
+)
+
+func TestAddCommitTypeChecksName(t *testing.T) {
+	t.Chdir(t.TempDir())
+	captureEvents(t)
+	for _, name := range []string{"", "Feat", "my type", "wip!", "ci/cd"} {
+		if err := commitTypesAddCmd.RunE(commitTypesAddCmd, []string{name}); err == nil {
+			t.Errorf("add %q was accepted", name)
+		}
+	}
+	if err := commitTypesAddCmd.RunE(commitTypesAddCmd, []string{"release-2"}); err != nil {
+		t.Errorf("add release-2: %v", err)
+	}
+}
+
+func TestAddCommitTypeKeepsReleaseItKeyOrder(t *testing.T) {
+	t.Chdir(t.TempDir())
+	captureEvents(t)
+	os.WriteFile(releaseItConfigFile, []byte(`{
+  "npm": {"publish": false},
+  "git": {"commitMessage": "chore: release v${version}", "tagName": "v${version}"},
+  "plugins": {
+    "@release-it/conventional-changelog": {
+      "infile": "CHANGELOG.md",
+      "preset": {"name": "conventionalcommits", "types": [{"type": "feat", "section": "Features"}]}
+    }
+  },
+  "github": {"release": true}
+}`), 0644)
+
+	if err := addCommitType(common.CommitType{Name: "perf", Section: common.ChangelogSection{En: "Performance & Speed"}}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(releaseItConfigFile)
+	want := `{
+  "npm": {
+    "publish": false
+  },
+  "git": {
+    "commitMessage": "chore: release v${version}",
+    "tagName": "v${version}"
+  },
+  "plugins": {
+    "@release-it/conventional-changelog": {
+      "infile": "CHANGELOG.md",
+      "preset": {
+        "name": "conventionalcommits",
+        "types": [
+          {
+            "type": "feat",
+            "section": "Features"
+          },
+          {
+            "type": "perf",
+            "section": "Performance & Speed"
+          }
+        ]
+      }
+    }
+  },
+  "github": {
+    "release": true
+  }
+}
+`
+	if string(data) != want {
+		t.Errorf("%s =\n%s\nwant\n%s", releaseItConfigFile, data, want)
+	}
+}
@@ -4,12 +4,15 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
+	"github.com/CrossEvol/setup/common"
 	"github.com/spf13/cobra"
 )
 
@@ -22,86 +25,99 @@ var commitlintCmd = &cobra.Command{
 It detects your package manager, installs commitlint CLI and conventional config,
 creates a configuration file (commitlint.config.cjs), adds a 'commitlint' script
 to package.json, and integrates with Husky if it's set up by adding a hook to
-.husky/commit-msg.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		setupCommitlint()
+.husky/commit-msg.
+
+With --native no node packages are installed: the config is still written, and
+a commit-msg hook runs 'setup commitlint check', the built-in Go linter.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		native, _ := cmd.Flags().GetBool("native")
+		if native {
+			return setupCommitlintNative()
+		}
+		return setupCommitlint()
 	},
 }
 
-func setupCommitlint() {
-	fmt.Println("Setting up commitlint...")
-
-	// Define package managers and their commands
-	packageManagers := []struct {
-		name       string
-		installCmd []string
-		runCmd     string // Command prefix for running scripts (e.g., "pnpm run", "npm run")
-	}{
-		{"pnpm", []string{"pnpm", "add", "-D", "@commitlint/cli", "@commitlint/config-conventional"}, "pnpm run"},
-		{"npm", []string{"npm", "install", "-D", "@commitlint/cli", "@commitlint/config-conventional"}, "npm run"},
-		{"yarn", []string{"yarn", "add", "-D", "@commitlint/cli", "@commitlint/config-conventional"}, "yarn run"},
-		{"bun", []string{"bun", "add", "-D", "@commitlint/cli", "@commitlint/config-conventional"}, "bun run"},
-	}
+// commitlintCheckCmd represents the commitlint check command
+var commitlintCheckCmd = &cobra.Command{
+	Use:   "check [msgfile]",
+	Short: "Lint a commit message without node",
+	Long: `Lint a commit message against the rules of commitlint.config.cjs.
 
-	var foundPackageManager string
-	var installCmdArgs []string
-	var runCmdPrefix string
-
-	// Check for package managers in order
-	for _, pm := range packageManagers {
-		_, err := exec.LookPath(pm.name)
-		if err == nil {
-			foundPackageManager = pm.name
-			installCmdArgs = pm.installCmd
-			runCmdPrefix = pm.runCmd
-			fmt.Printf("Found package manager: %s\n", foundPackageManager)
-			break // Use the first one found
+The message is read from msgfile (as passed to the commit-msg hook), from
+stdin when msgfile is "-", or from .git/COMMIT_EDITMSG by default. Problems
+are printed in the commitlint format and errors exit with status 1.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		msgFile := filepath.Join(".git", "COMMIT_EDITMSG")
+		if len(args) == 1 {
+			msgFile = args[0]
 		}
+
+		var data []byte
+		var err error
+		if msgFile == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(msgFile)
+		}
+		if err != nil {
+			return fmt.Errorf("reading commit message: %w", err)
+		}
+
+		if !checkCommitMessage(string(data)) {
+			return errCommitMessage
+		}
+		return nil
+	},
+}
+
+// checkCommitMessage prints the problems of a message like commitlint does
+// and reports whether it passed.
+func checkCommitMessage(raw string) bool {
+	if common.IsIgnoredMessage(raw) {
+		return true
 	}
 
-	if foundPackageManager == "" {
-		fmt.Println("Error: No supported package manager (pnpm, npm, yarn, bun) found.")
-		fmt.Println("Please install one of these package managers and try again.")
-		return
+	msg := common.ParseCommitMessage(raw)
+	problems := common.LintMessage(projectCommitlintRules(), msg)
+	if len(problems) == 0 {
+		return true
 	}
 
-	// 1. Install commitlint packages
-	fmt.Printf("Running installation command: %s %v\n", installCmdArgs[0], installCmdArgs[1:])
-	installCmd := exec.Command(installCmdArgs[0], installCmdArgs[1:]...)
-	installCmd.Stdout = os.Stdout
-	installCmd.Stderr = os.Stderr
-	err := installCmd.Run()
-	if err != nil {
-		fmt.Printf("Warning: Error installing commitlint packages with %s: %v\n", foundPackageManager, err)
-		fmt.Println("Attempting to continue assuming packages are already installed.")
-		// Continue setup even if installation fails, maybe they are already installed
-	} else {
-		fmt.Println("commitlint packages installed successfully.")
+	errorCount, warningCount := 0, 0
+	fmt.Printf("⧗   input: %s\n", msg.Header)
+	for _, problem := range problems {
+		sign := "⚠"
+		if problem.Level == common.LevelError {
+			sign = "✖"
+			errorCount++
+		} else {
+			warningCount++
+		}
+		fmt.Printf("%s   %s [%s]\n", sign, problem.Message, problem.Name)
+	}
+	fmt.Println()
+	sign := "⚠"
+	if errorCount > 0 {
+		sign = "✖"
 	}
+	fmt.Printf("%s   found %d problems, %d warnings\n", sign, errorCount, warningCount)
+	fmt.Println("ⓘ   Get help: https://github.com/conventional-changelog/commitlint/#what-is-commitlint")
+	fmt.Println()
 
-	// 2. Create commitlint.config.cjs file
-	const commitlintConfigFile = `commitlint.config.cjs`
-	const commitlintConfigContent = `module.exports = {
+	return errorCount == 0
+}
+
+// commitlintConfigTemplate is commitlint.config.cjs with the type-enum list left as a verb.
+const commitlintConfigTemplate = `module.exports = {
   extends: ['@commitlint/config-conventional'],
   rules: {
     'type-enum': [
       // type枚举
       2,
       'always',
-      [
-        'build', // 编译相关的修改，例如发布版本、对项目构建或者依赖的改动
-        'feat', // 新功能
-        'fix', // 修补bug
-        'docs', // 文档修改
-        'style', // 代码格式修改, 注意不是 css 修改
-        'refactor', // 重构
-        'perf', // 优化相关，比如提升性能、体验
-        'test', // 测试用例修改
-        'revert', // 代码回滚
-        'ci', // 持续集成修改
-        'config', // 配置修改
-        'chore', // 其他改动
-      ],
+      %s,
     ],
     'type-empty': [2, 'never'], // never: type不能为空; always: type必须为空
     'type-case': [0, 'always', 'lower-case'], // type必须小写，upper-case大写，camel-case小驼峰，kebab-case短横线，pascal-case大驼峰，等等
@@ -117,116 +133,136 @@ func setupCommitlint() {
 };
 `
 
-	fmt.Printf("Creating %s...\n", commitlintConfigFile)
-	err = os.WriteFile(commitlintConfigFile, []byte(commitlintConfigContent), 0644)
-	if err != nil {
-		fmt.Printf("Error creating %s: %v\n", commitlintConfigFile, err)
-		// Continue setup
-	} else {
-		fmt.Printf("%s created successfully.\n", commitlintConfigFile)
+// renderCommitlintConfig renders commitlint.config.cjs for the given commit types.
+func renderCommitlintConfig(types []common.CommitType) string {
+	return fmt.Sprintf(commitlintConfigTemplate, common.RenderTypeEnum(types))
+}
+
+// commitlintInstalls lists what setup commitlint installs.
+func commitlintInstalls(workspacePlan) []installRequest {
+	return []installRequest{{What: "commitlint packages", Packages: []string{"@commitlint/cli", "@commitlint/config-conventional"}}}
+}
+
+func setupCommitlint() error {
+	currentTool = COMMITLINT
+	reportInfo("Setting up commitlint...")
+
+	pm, found := detectPackageManager()
+	if !found {
+		return errNoPackageManager
 	}
+	runCmdPrefix := pm.RunCmd
 
-	// 3. Add "commitlint" script to package.json
-	packageJSONPath := "package.json"
-	packageJSONData, err := os.ReadFile(packageJSONPath)
+	// 1. Install commitlint packages
+	var errs []error
+	err := runInstalls(pm, commitlintInstalls(workspacePlan{}))
 	if err != nil {
-		fmt.Printf("Error reading package.json: %v\n", err)
-		// Cannot update package.json, stop here for this part
-	} else {
-		var pkgJSON map[string]interface{}
-		err = json.Unmarshal(packageJSONData, &pkgJSON)
-		if err != nil {
-			fmt.Printf("Error parsing package.json: %v\n", err)
-			// Cannot update package.json, stop here for this part
-		} else {
-			scripts, ok := pkgJSON["scripts"].(map[string]interface{})
-			if !ok {
-				// Scripts key doesn't exist or isn't a map, create it
-				scripts = make(map[string]interface{})
-				pkgJSON["scripts"] = scripts
-			}
-			scripts["commitlint"] = "commitlint --config commitlint.config.cjs -e -V"
-
-			updatedData, err := json.MarshalIndent(pkgJSON, "", "  ")
-			if err != nil {
-				fmt.Printf("Error marshalling updated package.json: %v\n", err)
-				// Cannot update package.json, stop here for this part
-			} else {
-				err = os.WriteFile(packageJSONPath, updatedData, 0644)
-				if err != nil {
-					fmt.Printf("Error writing updated package.json: %v\n", err)
-				} else {
-					fmt.Println("'commitlint' script added/updated in package.json.")
-				}
-			}
-		}
+		errs = append(errs, err)
+		reportWarning("Attempting to continue assuming packages are already installed.")
+		// Continue setup even if installation fails, maybe they are already installed
 	}
 
+	// 2. Create commitlint.config.cjs file
+	errs = append(errs, writeFile(commitlintConfigFile, []byte(renderCommitlintConfig(common.DefaultCommitTypes())), 0644))
+
+	// 3. Add "commitlint" script to package.json
+	errs = append(errs, setPackageScripts(".", map[string]string{"commitlint": "commitlint --config commitlint.config.cjs -e -V"}))
+
 	// 4. Integrate with Husky
 	huskyDir := ".husky"
 	huskyCommitMsgPath := filepath.Join(huskyDir, "commit-msg")
+	hookCommand := fmt.Sprintf("%s commitlint", runCmdPrefix)
 
 	_, err = os.Stat(huskyCommitMsgPath)
 	if err == nil { // File exists
-		fmt.Printf("Husky commit-msg hook found at %s. Appending commitlint command...\n", huskyCommitMsgPath)
-
-		// Read existing content
-		existingContent, readErr := os.ReadFile(huskyCommitMsgPath)
-		if readErr != nil {
-			fmt.Printf("Error reading %s: %v\n", huskyCommitMsgPath, readErr)
-			// Continue setup
-		} else {
-			// Append the command, ensuring it's on a new line and executable
-			// Add a newline if the file doesn't end with one
-			contentToAppend := fmt.Sprintf("%s commitlint\n", runCmdPrefix)
-			if len(existingContent) > 0 && existingContent[len(existingContent)-1] != '\n' {
-				contentToAppend = "\n" + contentToAppend
-			}
-
-			newContent := string(existingContent) + contentToAppend
-
-			// Write back the updated content
-			// Use 0755 permissions to ensure the hook is executable
-			writeErr := os.WriteFile(huskyCommitMsgPath, []byte(newContent), 0755)
-			if writeErr != nil {
-				fmt.Printf("Error writing to %s: %v\n", huskyCommitMsgPath, writeErr)
-			} else {
-				fmt.Printf("Command '%s commitlint' appended to %s.\n", runCmdPrefix, huskyCommitMsgPath)
-			}
-		}
+		errs = append(errs, appendHookLine(huskyCommitMsgPath, hookCommand))
 	} else if os.IsNotExist(err) { // File does not exist, create it
-		fmt.Printf("Husky commit-msg hook not found at %s. Creating file...\n", huskyCommitMsgPath)
-
-		// Ensure .husky directory exists
-		mkdirErr := os.MkdirAll(huskyDir, 0755) // Use 0755 for directory permissions
-		if mkdirErr != nil {
-			fmt.Printf("Error creating directory %s: %v\n", huskyDir, mkdirErr)
-			fmt.Println("Skipping Husky integration.")
-			return // Cannot proceed without the directory
-		}
+		errs = append(errs, createHook(huskyDir, huskyCommitMsgPath, hookCommand))
+	} else { // Some other error occurred checking the file
+		reportError("checking for %s: %v", huskyCommitMsgPath, err)
+		reportWarning("Skipping Husky integration.")
+		errs = append(errs, &configError{Path: huskyCommitMsgPath, Err: err})
+	}
 
-		// Content for the new commit-msg file
-		// Standard shebang + the command
-		newFileContent := fmt.Sprintf("#!/usr/bin/env sh\n%s commitlint\n", runCmdPrefix)
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	reportInfo("commitlint setup complete.")
+	return nil
+}
 
-		// Write the new file with executable permissions
-		writeErr := os.WriteFile(huskyCommitMsgPath, []byte(newFileContent), 0755)
-		if writeErr != nil {
-			fmt.Printf("Error creating %s: %v\n", huskyCommitMsgPath, writeErr)
-		} else {
-			fmt.Printf("%s created successfully with command '%s commitlint'.\n", huskyCommitMsgPath, runCmdPrefix)
+// createHook writes a new Git hook running a single command.
+func createHook(hookDir, hookPath, command string) error {
+	// Ensure the hooks directory exists
+	err := os.MkdirAll(hookDir, 0755)
+	if err != nil {
+		reportError("creating directory %s: %v", hookDir, err)
+		return &configError{Path: hookDir, Err: err}
+	}
+
+	// Standard shebang + the command, with executable permissions
+	err = os.WriteFile(hookPath, []byte("#!/usr/bin/env sh\n"+command+"\n"), 0755)
+	if err != nil {
+		reportError("creating %s: %v", hookPath, err)
+		return &configError{Path: hookPath, Err: err}
+	}
+	reportHookUpdated(hookPath, command, fmt.Sprintf("%s created successfully with command '%s'.", hookPath, command))
+	return nil
+}
+
+// setupCommitlintNative writes the config and a commit-msg hook that runs the
+// built-in linter instead of @commitlint/cli.
+func setupCommitlintNative() error {
+	currentTool = COMMITLINT
+	reportInfo("Setting up native commitlint...")
+
+	// 1. Create commitlint.config.cjs file unless it exists, it holds the rules
+	if _, err := os.Stat(commitlintConfigFile); os.IsNotExist(err) {
+		if err := writeFile(commitlintConfigFile, []byte(renderCommitlintConfig(common.DefaultCommitTypes())), 0644); err != nil {
+			return err
 		}
+	} else {
+		reportFileSkipped(commitlintConfigFile, "found, keeping its rules")
+	}
 
-	} else { // Some other error occurred checking the file
-		fmt.Printf("Error checking for %s: %v\n", huskyCommitMsgPath, err)
-		fmt.Println("Skipping Husky integration.")
+	// 2. Install the commit-msg hook, in .husky when Husky manages the hooks
+	const hookCommand = `setup commitlint check "$1"`
+	hookDir := ".husky"
+	if _, err := os.Stat(hookDir); err != nil {
+		out, err := exec.Command("git", "rev-parse", "--git-path", "hooks").Output()
+		if err != nil {
+			reportError("locating the git hooks directory: %v. Is this a git repository?", err)
+			return &configError{Path: "commit-msg", Err: err}
+		}
+		hookDir = strings.TrimSpace(string(out))
+	}
+	hookPath := filepath.Join(hookDir, "commit-msg")
+
+	existingContent, err := os.ReadFile(hookPath)
+	switch {
+	case err == nil && strings.Contains(string(existingContent), hookCommand):
+		reportFileSkipped(hookPath, fmt.Sprintf("already runs '%s'", hookCommand))
+	case err == nil:
+		err = appendHookLine(hookPath, hookCommand)
+	case os.IsNotExist(err):
+		err = createHook(hookDir, hookPath, hookCommand)
+	default:
+		reportError("reading %s: %v", hookPath, err)
+		err = &configError{Path: hookPath, Err: err}
+	}
+	if err != nil {
+		return err
 	}
 
-	fmt.Println("commitlint setup complete.")
+	reportInfo("commitlint setup complete.")
+	return nil
 }
 
 func init() {
 	rootCmd.AddCommand(commitlintCmd)
+	commitlintCmd.AddCommand(commitlintCheckCmd)
+
+	commitlintCmd.Flags().Bool("native", false, "Use the built-in Go linter in the commit-msg hook instead of @commitlint/cli")
 
 	// Here you will define your flags and configuration settings.
 
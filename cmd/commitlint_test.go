@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/CrossEvol/setup/common"
+)
+
+func TestGeneratedCommitlintRules(t *testing.T) {
+	rules := common.ParseCommitlintRules(renderCommitlintConfig(common.DefaultCommitTypes()))
+
+	tests := []struct {
+		name, message string
+		want          []string
+	}{
+		{"valid", "feat(cli): add a flag\n\nIt is off by default.", nil},
+		{"type from the catalog", "chore: bump the lockfile", nil},
+		{"unknown type", "feature: add a flag", []string{"type-enum"}},
+		// Disabled by the generated config, unlike the preset
+		{"subject case", "fix: Handle empty input.", nil},
+		{"no blank line", "fix: handle empty input\nThe parser returned early.", nil},
+		// Tightened by the generated config
+		{"long header", "fix: " + strings.Repeat("x", 68), []string{"header-max-length"}},
+		// Left to the preset
+		{"header whitespace", " fix: handle empty input", []string{"type-empty", "subject-empty", "header-trim"}},
+		{"long body line", "fix: handle empty input\n\n" + strings.Repeat("x", 101), []string{"body-max-line-length"}},
+		{"long footer line", "fix: handle empty input\n\nRefs: " + strings.Repeat("x", 101), []string{"footer-max-line-length"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, problem := range common.LintMessage(rules, common.ParseCommitMessage(tt.message)) {
+				got = append(got, problem.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("problems = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
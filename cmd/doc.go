@@ -8,8 +8,6 @@ import (
 	"fmt"
 	"github.com/CrossEvol/setup/assets"
 	"github.com/charmbracelet/huh"
-	"log"
-	"os"
 	"os/exec"
 	"runtime"
 	"sort"
@@ -28,22 +26,21 @@ Available tools: eslint, tslint, prettier, vitest and so on.
 
 Example usage:
   yourapp doc eslint or yourapp doc , if you pass the name, it will open the corresponding doc. If not, it will provide you multi selections.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		var docPairs map[string]string
 		data := assets.DocPairs
 		if err := json.Unmarshal(data, &docPairs); err != nil {
-			log.Fatalf("Failed to load Doc options: %v", err)
+			return fmt.Errorf("loading the doc options: %w", err)
 		}
 
 		if len(args) == 1 {
 			tool := args[0]
 			url, ok := docPairs[strings.ToLower(tool)]
 			if !ok {
-				fmt.Printf("Unknown tool: %s\n", tool)
-				return
+				return fmt.Errorf("unknown tool: %s", tool)
 			}
 			openBrowser(url)
-			return
+			return nil
 		}
 
 		var options []huh.Option[string]
@@ -72,15 +69,14 @@ Example usage:
 		err := form.Run()
 
 		if err != nil {
-			fmt.Println("Uh oh:", err)
-			os.Exit(1)
+			return err
 		}
 
 		for _, selection := range selections {
 			split := strings.Split(selection, "--->")
 			openBrowser(strings.TrimSpace(split[1]))
 		}
-
+		return nil
 	},
 }
 
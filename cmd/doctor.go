@@ -0,0 +1,440 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/CrossEvol/setup/common"
+	"github.com/spf13/cobra"
+)
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// finding is a problem reported by 'setup doctor'.
+type finding struct {
+	Severity string
+	Message  string
+	Fix      string
+}
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Audit the tooling set up in the project",
+	Long: `Inspect the project and report problems in the tooling this CLI sets up:
+scripts calling binaries that are not dependencies, scripts referenced by
+lint-staged or Husky hooks that do not exist, Husky hooks that are not executable
+or use CRLF line endings, Prettier running both through ESLint and standalone,
+a missing 'prepare' script for Husky, multiple lockfiles and installed versions
+outside the tested ranges of the version manifest.
+
+Findings are grouped by severity with the 'setup' command that fixes each one.
+The command exits with status 1 when errors are found, so it can run in CI.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		findings := runDoctor()
+		printFindings(findings)
+		errorCount := 0
+		for _, f := range findings {
+			if f.Severity == severityError {
+				errorCount++
+			}
+		}
+		if errorCount > 0 {
+			return fmt.Errorf("doctor found %d errors", errorCount)
+		}
+		return nil
+	},
+}
+
+// packageJSON holds the parts of package.json inspected by doctor and status.
+type packageJSON struct {
+	Scripts         map[string]string `json:"scripts"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+	LintStaged      json.RawMessage   `json:"lint-staged"`
+}
+
+// readPackageJSON parses package.json in the current directory.
+func readPackageJSON() (packageJSON, error) {
+	var pkg packageJSON
+	data, err := os.ReadFile("package.json")
+	if err != nil {
+		return pkg, err
+	}
+	err = json.Unmarshal(data, &pkg)
+	return pkg, err
+}
+
+// hasDependency reports whether a package is a dependency or dev dependency.
+func (p packageJSON) hasDependency(name string) bool {
+	_, dep := p.Dependencies[name]
+	_, devDep := p.DevDependencies[name]
+	return dep || devDep
+}
+
+// binaryPackages maps binaries to the package that provides them when the names differ.
+var binaryPackages = map[string]string{
+	"tsc":        "typescript",
+	"commitlint": "@commitlint/cli",
+	"changeset":  "@changesets/cli",
+	"ng":         "@angular/cli",
+	"vue-tsc":    "vue-tsc",
+	"nest":       "@nestjs/cli",
+	"playwright": "@playwright/test",
+	"biome":      "@biomejs/biome",
+}
+
+// shellBuiltins are commands expected on the PATH rather than in node_modules.
+var shellBuiltins = map[string]bool{
+	"node": true, "npm": true, "npx": true, "pnpm": true, "pnpx": true, "yarn": true, "bun": true, "bunx": true,
+	"echo": true, "exit": true, "cd": true, "rm": true, "cp": true, "mv": true, "mkdir": true, "cat": true,
+	"true": true, "false": true, "test": true, "sh": true, "bash": true, "git": true, "setup": true, "export": true,
+	"set": true, "if": true, "then": true, "else": true, "fi": true, "[": true, "exec": true, "corepack": true,
+}
+
+// setupCommands maps binaries to the command that installs and configures them.
+var setupCommands = map[string]string{
+	"eslint":      "setup eslint",
+	"prettier":    "setup prettier",
+	"stylelint":   "setup stylelint",
+	"vitest":      "setup vitest",
+	"husky":       "setup husky",
+	"commitlint":  "setup commitlint",
+	"lint-staged": "setup lintStaged",
+	"release-it":  "setup releaseIt",
+	"changeset":   "setup changesets",
+}
+
+var scriptSeparators = regexp.MustCompile(`&&|\|\||;|\|`)
+
+// scriptBinaries returns the binaries a script invokes.
+func scriptBinaries(script string) []string {
+	var binaries []string
+	for _, part := range scriptSeparators.Split(script, -1) {
+		fields := strings.Fields(part)
+		// Skip environment assignments and package-manager runners
+		for len(fields) > 0 {
+			field := fields[0]
+			switch {
+			case strings.Contains(field, "=") && !strings.HasPrefix(field, "-"):
+				fields = fields[1:]
+				continue
+			case field == "cross-env" || field == "npx" || field == "bunx" || field == "pnpx":
+				binaries = append(binaries, field)
+				fields = fields[1:]
+				for len(fields) > 0 && strings.HasPrefix(fields[0], "-") {
+					fields = fields[1:]
+				}
+				continue
+			case (field == "pnpm" || field == "yarn") && len(fields) > 1 && fields[1] == "exec":
+				fields = fields[2:]
+				continue
+			}
+			break
+		}
+		if len(fields) > 0 {
+			binaries = append(binaries, strings.Trim(fields[0], `"'()`))
+		}
+	}
+	return binaries
+}
+
+// binaryAvailable reports whether a binary is provided by a dependency.
+func (p packageJSON) binaryAvailable(binary string) bool {
+	if shellBuiltins[binary] || strings.ContainsAny(binary, "/.$") || binary == "" {
+		return true
+	}
+	if p.hasDependency(binary) {
+		return true
+	}
+	if pkg, ok := binaryPackages[binary]; ok && p.hasDependency(pkg) {
+		return true
+	}
+	if _, err := os.Stat(filepath.Join("node_modules", ".bin", binary)); err == nil {
+		return true
+	}
+	return false
+}
+
+var runScriptPattern = regexp.MustCompile(`\b(npm|pnpm|yarn|bun)( run)? ([a-zA-Z][\w:.-]*)`)
+
+// packageManagerCommands are the commands that do not run a script when
+// written without "run": "pnpm lint" runs the lint script, "pnpm install" does
+// not. npm only runs a few scripts that way, bun test is bun's test runner.
+var packageManagerCommands = map[string][]string{
+	"pnpm": {"add", "audit", "bin", "config", "create", "dedupe", "deploy", "dlx", "env", "exec", "fetch", "i", "import",
+		"init", "install", "link", "licenses", "list", "ls", "outdated", "pack", "patch", "prune", "publish", "rebuild",
+		"remove", "rm", "root", "setup", "store", "uninstall", "unlink", "up", "update", "upgrade", "why"},
+	"yarn": {"add", "bin", "cache", "config", "constraints", "create", "dedupe", "dlx", "exec", "explain", "global", "info",
+		"init", "install", "link", "node", "npm", "pack", "plugin", "publish", "remove", "set", "unlink", "up", "upgrade",
+		"version", "why", "workspace", "workspaces"},
+	"bun": {"add", "build", "create", "exec", "i", "init", "install", "link", "outdated", "patch", "pm", "publish", "remove",
+		"rm", "test", "unlink", "update", "upgrade", "x"},
+}
+
+// npmScriptShorthands are the scripts npm runs without "run".
+var npmScriptShorthands = map[string]string{"test": "test", "t": "test", "start": "start", "stop": "stop", "restart": "restart"}
+
+// scriptReference is a package.json script run by a command line.
+type scriptReference struct {
+	Name string
+	// Shorthand is set without "run": pnpm, yarn and bun then run the binary
+	// of that name when there is no such script.
+	Shorthand bool
+}
+
+// scriptReferences returns the package.json scripts run by a command line or
+// file, "lint" for "pnpm run lint" as for "pnpm lint".
+func scriptReferences(text string) []scriptReference {
+	var scripts []scriptReference
+	for _, match := range runScriptPattern.FindAllStringSubmatch(text, -1) {
+		manager, run, script := match[1], match[2] != "", match[3]
+		switch {
+		case run:
+		case manager == "npm":
+			var ok bool
+			if script, ok = npmScriptShorthands[script]; !ok {
+				continue
+			}
+		case slices.Contains(packageManagerCommands[manager], script):
+			continue
+		}
+		scripts = append(scripts, scriptReference{Name: script, Shorthand: !run && manager != "npm"})
+	}
+	return scripts
+}
+
+// lintStagedSources returns the lint-staged configurations of the project.
+func lintStagedSources(pkg packageJSON) map[string]string {
+	sources := make(map[string]string)
+	for _, name := range []string{"lint-staged.config.js", "lint-staged.config.mjs", "lint-staged.config.cjs", ".lintstagedrc", ".lintstagedrc.json", ".lintstagedrc.js", ".lintstagedrc.mjs", ".lintstagedrc.cjs", ".lintstagedrc.yaml", ".lintstagedrc.yml"} {
+		if data, err := os.ReadFile(name); err == nil {
+			sources[name] = string(data)
+		}
+	}
+	if len(pkg.LintStaged) > 0 {
+		sources["package.json#lint-staged"] = string(pkg.LintStaged)
+	}
+	return sources
+}
+
+// huskyHooks returns the hook files in .husky, skipping Husky's own directory.
+func huskyHooks() []string {
+	entries, err := os.ReadDir(".husky")
+	if err != nil {
+		return nil
+	}
+	var hooks []string
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		hooks = append(hooks, filepath.Join(".husky", entry.Name()))
+	}
+	return hooks
+}
+
+func runDoctor() []finding {
+	var findings []finding
+
+	pkg, err := readPackageJSON()
+	if err != nil {
+		return []finding{{severityError, fmt.Sprintf("Cannot read package.json: %v", err), "run 'npm init' or move to the project root"}}
+	}
+
+	// 1. Scripts calling binaries that are not dependencies
+	scriptNames := make([]string, 0, len(pkg.Scripts))
+	for name := range pkg.Scripts {
+		scriptNames = append(scriptNames, name)
+	}
+	sort.Strings(scriptNames)
+	for _, name := range scriptNames {
+		for _, binary := range scriptBinaries(pkg.Scripts[name]) {
+			if pkg.binaryAvailable(binary) {
+				continue
+			}
+			fix := fmt.Sprintf("add the package providing '%s' to devDependencies", binary)
+			if command, ok := setupCommands[binary]; ok {
+				fix = command
+			}
+			findings = append(findings, finding{severityError,
+				fmt.Sprintf("Script '%s' calls '%s', which is not in dependencies or devDependencies", name, binary), fix})
+		}
+	}
+
+	// 2. Scripts referenced by lint-staged and Husky hooks but missing
+	references := lintStagedSources(pkg)
+	hooks := huskyHooks()
+	for _, hook := range hooks {
+		if data, err := os.ReadFile(hook); err == nil {
+			references[hook] = string(data)
+		}
+	}
+	referenceNames := make([]string, 0, len(references))
+	for name := range references {
+		referenceNames = append(referenceNames, name)
+	}
+	sort.Strings(referenceNames)
+	for _, source := range referenceNames {
+		seen := make(map[string]bool)
+		for _, reference := range scriptReferences(references[source]) {
+			script := reference.Name
+			if _, ok := pkg.Scripts[script]; ok || seen[script] || reference.Shorthand && pkg.binaryAvailable(script) {
+				continue
+			}
+			seen[script] = true
+			fix := fmt.Sprintf("add a '%s' script to package.json", script)
+			switch script {
+			case "format":
+				fix = "add \"format\": \"prettier . --write\" to the scripts in package.json ('setup prettier' names this script 'prettier')"
+			case "lint":
+				fix = "setup eslint"
+			case "test":
+				fix = "setup vitest"
+			case "commitlint":
+				fix = "setup commitlint"
+			case "pre-commit":
+				fix = "setup lintStaged"
+			}
+			findings = append(findings, finding{severityError,
+				fmt.Sprintf("%s runs the '%s' script, which is missing from package.json", source, script), fix})
+		}
+	}
+
+	// 3. Husky hooks that are not executable or have CRLF line endings
+	for _, hook := range hooks {
+		info, err := os.Stat(hook)
+		if err != nil {
+			continue
+		}
+		// Windows has no executable bit, git keeps it in the index instead
+		if runtime.GOOS != "windows" && info.Mode().Perm()&0111 == 0 {
+			findings = append(findings, finding{severityError,
+				fmt.Sprintf("Husky hook %s is not executable", hook), fmt.Sprintf("chmod +x %s", hook)})
+		}
+		if data, err := os.ReadFile(hook); err == nil && bytes.Contains(data, []byte("\r\n")) {
+			findings = append(findings, finding{severityError,
+				fmt.Sprintf("Husky hook %s has CRLF line endings and will fail in sh", hook), "convert it to LF, or run 'setup husky' to recreate the hooks"})
+		}
+	}
+
+	// 4. Prettier running both through ESLint and standalone
+	if pkg.hasDependency("eslint-plugin-prettier") {
+		var standalone []string
+		for _, name := range scriptNames {
+			if slices.Contains(scriptBinaries(pkg.Scripts[name]), "prettier") {
+				standalone = append(standalone, fmt.Sprintf("script '%s'", name))
+			}
+		}
+		for _, source := range referenceNames {
+			if strings.Contains(references[source], "prettier") && !strings.HasPrefix(source, ".husky") {
+				standalone = append(standalone, source)
+			}
+		}
+		if len(standalone) > 0 {
+			findings = append(findings, finding{severityWarning,
+				fmt.Sprintf("eslint-plugin-prettier formats through ESLint, but Prettier also runs standalone in %s", strings.Join(standalone, ", ")),
+				"setup linter for the ESLint-only flow, or drop eslint-plugin-prettier and keep 'setup prettier'"})
+		}
+	}
+
+	// 5. Missing prepare script for Husky
+	if pkg.hasDependency("husky") && !strings.Contains(pkg.Scripts["prepare"], "husky") {
+		findings = append(findings, finding{severityError,
+			"husky is installed but no 'prepare' script runs it, so hooks are not installed on a fresh clone", "setup husky"})
+	}
+
+	// 6. Multiple lockfiles
+	var locks []string
+	for _, lock := range []string{"package-lock.json", "pnpm-lock.yaml", "yarn.lock", "bun.lockb", "bun.lock"} {
+		if _, err := os.Stat(lock); err == nil {
+			locks = append(locks, lock)
+		}
+	}
+	if len(locks) > 1 {
+		findings = append(findings, finding{severityWarning,
+			fmt.Sprintf("Multiple lockfiles found: %s", strings.Join(locks, ", ")),
+			"delete the lockfiles of the package managers you do not use"})
+	}
+
+	// 7. Versions outside the tested ranges of the version manifest
+	manifest := common.DefaultVersionManifest()
+	var dependencies []string
+	for name := range pkg.Dependencies {
+		dependencies = append(dependencies, name)
+	}
+	for name := range pkg.DevDependencies {
+		dependencies = append(dependencies, name)
+	}
+	sort.Strings(dependencies)
+	for _, name := range slices.Compact(dependencies) {
+		rng, ok := manifest.Range(name)
+		if !ok {
+			continue
+		}
+		version, source := common.LockedVersion(name)
+		if version == "" {
+			declared := pkg.DevDependencies[name]
+			if declared == "" {
+				declared = pkg.Dependencies[name]
+			}
+			version, source = common.MinVersion(declared), "package.json"
+		}
+		if version == "" || common.SatisfiesRange(version, rng) {
+			continue
+		}
+		findings = append(findings, finding{severityWarning,
+			fmt.Sprintf("%s %s (%s) is outside the tested range %s", name, version, source, rng),
+			fmt.Sprintf("setup %s to install the tested versions", manifest.ToolSet(name))})
+	}
+
+	return findings
+}
+
+func printFindings(findings []finding) {
+	if len(findings) == 0 {
+		fmt.Println("No problems found!")
+		return
+	}
+
+	for _, severity := range []string{severityError, severityWarning} {
+		var group []finding
+		for _, f := range findings {
+			if f.Severity == severity {
+				group = append(group, f)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+
+		sign, title := "✖", "Errors"
+		if severity == severityWarning {
+			sign, title = "⚠", "Warnings"
+		}
+		fmt.Printf("%s (%d)\n", title, len(group))
+		for _, f := range group {
+			fmt.Printf("  %s %s\n", sign, f.Message)
+			fmt.Printf("    fix: %s\n", f.Fix)
+		}
+		fmt.Println()
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScriptReferences(t *testing.T) {
+	tests := []struct {
+		line string
+		want []scriptReference
+	}{
+		{"npm run lint", []scriptReference{{"lint", false}}},
+		{"pnpm run build:types", []scriptReference{{"build:types", false}}},
+		{"pnpm lint", []scriptReference{{"lint", true}}},
+		{"yarn format && bun typecheck", []scriptReference{{"format", true}, {"typecheck", true}}},
+		{"npm test", []scriptReference{{"test", false}}},
+		{"npm t", []scriptReference{{"test", false}}},
+		{"npm lint", nil},
+		{"npm ci && pnpm install --frozen-lockfile && yarn dlx tsc", nil},
+		{"bun test", nil},
+		{"bun run test", []scriptReference{{"test", false}}},
+		{"pnpm --filter web lint", nil},
+		{"npx lint-staged", nil},
+	}
+	for _, tt := range tests {
+		if got := scriptReferences(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("scriptReferences(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestDoctorMissingScripts(t *testing.T) {
+	t.Chdir(t.TempDir())
+	os.WriteFile("package.json", []byte(`{"scripts": {"lint": "eslint ."}, "devDependencies": {"eslint": "^9.9.0", "lint-staged": "^15.2.0"}}`), 0644)
+	os.Mkdir(".husky", 0755)
+	os.WriteFile(".husky/pre-commit", []byte("pnpm lint\npnpm lint-staged\nyarn format\nnpm test\n"), 0755)
+
+	var missing []string
+	for _, f := range runDoctor() {
+		if strings.Contains(f.Message, "missing from package.json") {
+			missing = append(missing, f.Message)
+			if strings.Contains(f.Message, "'format'") && !strings.Contains(f.Fix, `"format": "prettier . --write"`) {
+				t.Errorf("fix for format = %q", f.Fix)
+			}
+		}
+	}
+	want := []string{
+		".husky/pre-commit runs the 'format' script, which is missing from package.json",
+		".husky/pre-commit runs the 'test' script, which is missing from package.json",
+	}
+	if !reflect.DeepEqual(missing, want) {
+		t.Errorf("missing scripts = %q, want %q", missing, want)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Exit codes of the CLI, so CI can tell the failures apart.
+const (
+	exitFailure          = 1 // any other error, e.g. an aborted form or bad flags
+	exitNoPackageManager = 3 // no supported package manager is installed
+	exitInstallFailed    = 4 // a package manager command failed
+	exitPartialConfig    = 5 // some config files, scripts or hooks could not be written
+)
+
+// errNoPackageManager is returned when none of pnpm, npm, yarn or bun is installed.
+var errNoPackageManager = errors.New("no supported package manager (pnpm, npm, yarn, bun) found")
+
+// errOffline is returned by commands that need the network under --offline.
+var errOffline = errors.New("network access is disabled by --offline")
+
+// errCommitMessage is returned when a commit message breaks the commitlint rules.
+var errCommitMessage = errors.New("the commit message does not follow the commitlint rules")
+
+// installError is returned when installing or uninstalling packages fails.
+// What describes the command, e.g. "installing Husky".
+type installError struct {
+	What string
+	Err  error
+}
+
+func (e *installError) Error() string {
+	return fmt.Sprintf("%s: %v", e.What, e.Err)
+}
+
+func (e *installError) Unwrap() error {
+	return e.Err
+}
+
+// configError is returned when a config file, script or hook could not be
+// written, leaving the tool partially configured.
+type configError struct {
+	Path string
+	Err  error
+}
+
+func (e *configError) Error() string {
+	return fmt.Sprintf("configuring %s: %v", e.Path, e.Err)
+}
+
+func (e *configError) Unwrap() error {
+	return e.Err
+}
+
+// stepsError is returned by 'setup node' when some tools failed; the details
+// are in the summary table, the wrapped errors decide the exit status.
+type stepsError struct {
+	Failed int
+	Total  int
+	Err    error
+}
+
+func (e *stepsError) Error() string {
+	return fmt.Sprintf("%d of %d tools were not set up completely", e.Failed, e.Total)
+}
+
+func (e *stepsError) Unwrap() error {
+	return e.Err
+}
+
+// exitCode maps an error returned by a command to the exit status. When
+// several errors were joined, the most fundamental one wins.
+func exitCode(err error) int {
+	var install *installError
+	var config *configError
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errNoPackageManager):
+		return exitNoPackageManager
+	case errors.As(err, &install):
+		return exitInstallFailed
+	case errors.As(err, &config):
+		return exitPartialConfig
+	default:
+		return exitFailure
+	}
+}
+
+// resultStatus describes the outcome of setting a tool up in a summary.
+func resultStatus(err error) string {
+	switch exitCode(err) {
+	case 0:
+		return "ok"
+	case exitNoPackageManager:
+		return "no package manager"
+	case exitInstallFailed:
+		return "install failed"
+	case exitPartialConfig:
+		return "partial"
+	default:
+		return "failed"
+	}
+}
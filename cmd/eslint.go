@@ -4,10 +4,8 @@ Copyright © 2024 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"os/exec"
 
 	"github.com/spf13/cobra"
 )
@@ -20,15 +18,20 @@ var eslintCmd = &cobra.Command{
 
 It installs the necessary dependencies, creates an ESLint configuration file,
 and adds a lint script to your package.json. This helps maintain code quality
-and consistency in your JavaScript and TypeScript projects.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		setupEslint()
+and consistency in your JavaScript and TypeScript projects.
+
+Inside a monorepo it asks whether to set ESLint up at the workspace root, in
+each package, or both with package scripts delegating to the root config.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setupEslint()
 	},
 }
 
-func setupEslint() {
-	const eslintFile = `eslint.config.mjs`
-	const eslintConfig = `
+// eslintConfigFile is the flat config written by setup eslint and setup linter.
+const eslintConfigFile = `eslint.config.mjs`
+
+// eslintConfig is the ESLint config written by setup eslint.
+const eslintConfig = `
 import pluginJs from "@eslint/js";
 import globals from "globals";
 import tseslint from "typescript-eslint";
@@ -57,100 +60,50 @@ export default [
   ...tseslint.configs.recommended,
 ];
 `
-	fmt.Println("eslint called")
 
-	// Create eslint.config.mjs file
-	err := os.WriteFile(eslintFile, []byte(eslintConfig), 0644)
-	if err != nil {
-		fmt.Printf("Error creating %s: %v\n", eslintFile, err)
-	} else {
-		fmt.Printf("%s created successfully\n", eslintFile)
-	}
+// eslintPackages are the dev dependencies of setup eslint and setup linter.
+var eslintPackages = []string{"eslint", "globals", "@eslint/js", "typescript-eslint"}
 
-	// Define package managers and their commands
-	packageManagers := []struct {
-		name       string
-		installCmd []string
-	}{
-		{"pnpm", []string{"pnpm", "add", "-D", "eslint", "globals", "@eslint/js", "typescript-eslint"}},
-		{"npm", []string{"npm", "install", "--save-dev", "eslint", "globals", "@eslint/js", "typescript-eslint"}},
-		{"yarn", []string{"yarn", "add", "--dev", "eslint", "globals", "@eslint/js", "typescript-eslint"}},
-		{"bun", []string{"bun", "add", "--dev", "eslint", "globals", "@eslint/js", "typescript-eslint"}},
-	}
+// eslintInstalls lists what setup eslint installs under the workspace plan.
+func eslintInstalls(plan workspacePlan) []installRequest {
+	return plan.installRequests("ESLint and dependencies", eslintPackages, false)
+}
 
-	var foundPackageManager string
-	var installCmdArgs []string
-
-	// Check for package managers in order
-	for _, pm := range packageManagers {
-		_, err := exec.LookPath(pm.name)
-		if err == nil {
-			foundPackageManager = pm.name
-			installCmdArgs = pm.installCmd
-			fmt.Printf("Found package manager: %s\n", foundPackageManager)
-			break // Use the first one found
-		}
+func setupEslint() error {
+	currentTool = ESLINT
+	reportInfo("eslint called")
+
+	var errs []error
+	pm, found := detectPackageManager()
+	if !found {
+		errs = append(errs, errNoPackageManager)
 	}
 
-	if foundPackageManager == "" {
-		fmt.Println("Error: No supported package manager (pnpm, npm, yarn, bun) found.")
-		fmt.Println("Please install one of these package managers and try again.")
-		// Decide if you want to return or try a default
-		// For now, let's try to proceed with a default or let it fail if no command is set
-		// Or, more safely, return:
-		// return
+	plan, err := askWorkspacePlan(ESLINT)
+	if err != nil {
+		return err
+	}
+	if found {
+		errs = append(errs, runInstalls(pm, eslintInstalls(plan)))
 	}
 
-	// Run install command
-	if len(installCmdArgs) > 0 {
-		fmt.Printf("Running installation command: %s %v\n", installCmdArgs[0], installCmdArgs[1:])
-		installCmd := exec.Command(installCmdArgs[0], installCmdArgs[1:]...)
-		installCmd.Stdout = os.Stdout
-		installCmd.Stderr = os.Stderr
-		err = installCmd.Run()
-		if err != nil {
-			fmt.Printf("Error installing ESLint with %s: %v\n", foundPackageManager, err)
-		} else {
-			fmt.Println("ESLint and dependencies installed successfully.")
-		}
-	} else if foundPackageManager != "" {
-		// This case should ideally not be reached if packageManagers struct is well-defined
-		fmt.Printf("Package manager %s found, but no install command configured for ESLint.\n", foundPackageManager)
+	// Shared config and script at the root
+	if plan.atRoot() {
+		errs = append(errs, writeConfigFile(".", eslintConfigFile, eslintConfig))
+		errs = append(errs, setPackageScripts(".", map[string]string{"lint": "eslint . --fix"}))
 	}
-	// If no package manager was found and we didn't return, the original pnpm command would have run.
-	// The logic above replaces that.
 
-	// Update package.json
-	packageJSONPath := "package.json"
-	packageJSONData, err := os.ReadFile(packageJSONPath)
-	if err != nil {
-		fmt.Printf("Error reading package.json: %v\n", err)
-	} else {
-		var pkgJSON map[string]interface{}
-		err = json.Unmarshal(packageJSONData, &pkgJSON)
-		if err != nil {
-			fmt.Printf("Error parsing package.json: %v\n", err)
+	// Per-package configs, or package scripts delegating to the root config
+	for _, pkg := range plan.Packages {
+		if plan.Mode == workspacePackages {
+			errs = append(errs, writeConfigFile(pkg.Dir, eslintConfigFile, eslintConfig))
+			errs = append(errs, setPackageScripts(pkg.Dir, map[string]string{"lint": "eslint . --fix"}))
 		} else {
-			scripts, ok := pkgJSON["scripts"].(map[string]interface{})
-			if !ok {
-				scripts = make(map[string]interface{})
-				pkgJSON["scripts"] = scripts
-			}
-			scripts["lint"] = "eslint . --fix"
-
-			updatedData, err := json.MarshalIndent(pkgJSON, "", "  ")
-			if err != nil {
-				fmt.Printf("Error marshalling updated package.json: %v\n", err)
-			} else {
-				err = os.WriteFile(packageJSONPath, updatedData, 0644)
-				if err != nil {
-					fmt.Printf("Error writing updated package.json: %v\n", err)
-				} else {
-					fmt.Println("'lint' script added/updated in package.json.")
-				}
-			}
+			errs = append(errs, setPackageScripts(pkg.Dir, map[string]string{"lint": fmt.Sprintf("eslint . --fix --config %s", rootConfigPath(pkg.Dir, eslintConfigFile))}))
 		}
 	}
+	errs = append(errs, plan.delegateFromRoot(pm, found, "lint"))
+	return errors.Join(errs...)
 }
 
 func init() {
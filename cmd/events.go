@@ -0,0 +1,199 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// outputFormat is the value of the global --output flag.
+var outputFormat = outputText
+
+// Event types reported while setting tools up.
+const (
+	eventStepStarted     = "step_started"
+	eventStepFinished    = "step_finished"
+	eventInfo            = "info"
+	eventFileWritten     = "file_written"
+	eventFileSkipped     = "file_skipped"
+	eventFileDeleted     = "file_deleted"
+	eventCommandStarted  = "command_started"
+	eventCommandFinished = "command_finished"
+	eventScriptSet       = "script_set"
+	eventScriptRemoved   = "script_removed"
+	eventHookUpdated     = "hook_updated"
+	eventWarning         = "warning"
+	eventError           = "error"
+	eventSummary         = "summary"
+)
+
+// event is a step of a setup command. In JSON mode every event is printed as
+// one line; in text mode Message is printed.
+type event struct {
+	Type     string   `json:"type"`
+	Tool     string   `json:"tool,omitempty"`
+	Path     string   `json:"path,omitempty"`
+	Command  []string `json:"command,omitempty"`
+	ExitCode *int     `json:"exit_code,omitempty"`
+	Script   string   `json:"script,omitempty"`
+	Value    string   `json:"value,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// currentTool is the tool being set up, attached to every event. The setup
+// functions set it first thing.
+var currentTool string
+
+// eventSink is where events are written.
+var eventSink io.Writer = os.Stdout
+
+func emit(e event) {
+	if e.Tool == "" {
+		e.Tool = currentTool
+	}
+	if outputFormat == outputJSON {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return
+		}
+		fmt.Fprintln(eventSink, string(data))
+		return
+	}
+	switch e.Type {
+	case eventWarning:
+		fmt.Fprintln(eventSink, "Warning: "+e.Message)
+	case eventError:
+		fmt.Fprintln(eventSink, "Error: "+e.Message)
+	default:
+		fmt.Fprintln(eventSink, e.Message)
+	}
+}
+
+// commandOutput is where the output of the package managers goes; it is kept
+// out of the event stream in JSON mode.
+func commandOutput() io.Writer {
+	if outputFormat == outputJSON {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// stepResult is the outcome of setting one tool up in 'setup node'.
+type stepResult struct {
+	Tool string
+	Err  error
+}
+
+// runStep reports the start and end of setting a tool up in 'setup node'.
+func runStep(tool, title string, setup func() error) stepResult {
+	emit(event{Type: eventStepStarted, Tool: tool, Message: fmt.Sprintf("\n=============== Setup %s BEGIN  =====================", title)})
+	err := setup()
+	emit(event{Type: eventStepFinished, Tool: tool, Value: resultStatus(err), Message: fmt.Sprintf("=============== Setup %s END  =====================\n", title)})
+	return stepResult{Tool: tool, Err: err}
+}
+
+// printSummary reports the outcome of every step, as a table in text mode and
+// as one summary event per tool in JSON mode.
+func printSummary(results []stepResult) {
+	if outputFormat == outputJSON {
+		for _, result := range results {
+			message := ""
+			if result.Err != nil {
+				message = result.Err.Error()
+			}
+			emit(event{Type: eventSummary, Tool: result.Tool, Value: resultStatus(result.Err), Message: message})
+		}
+		return
+	}
+
+	fmt.Fprintln(eventSink, "Summary")
+	w := tabwriter.NewWriter(eventSink, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  TOOL\tSTATUS\tDETAILS")
+	for _, result := range results {
+		details := ""
+		if result.Err != nil {
+			// Joined errors are reported one per line; the first one is enough here
+			details, _, _ = strings.Cut(result.Err.Error(), "\n")
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", result.Tool, resultStatus(result.Err), details)
+	}
+	w.Flush()
+}
+
+func reportInfo(format string, a ...any) {
+	emit(event{Type: eventInfo, Message: fmt.Sprintf(format, a...)})
+}
+
+func reportWarning(format string, a ...any) {
+	emit(event{Type: eventWarning, Message: fmt.Sprintf(format, a...)})
+}
+
+func reportError(format string, a ...any) {
+	emit(event{Type: eventError, Message: fmt.Sprintf(format, a...)})
+}
+
+func reportFileWritten(path string) {
+	emit(event{Type: eventFileWritten, Path: path, Message: fmt.Sprintf("%s created successfully.", path)})
+}
+
+func reportFileUpdated(path string) {
+	emit(event{Type: eventFileWritten, Path: path, Message: fmt.Sprintf("%s updated.", path)})
+}
+
+func reportFileSkipped(path, reason string) {
+	emit(event{Type: eventFileSkipped, Path: path, Value: reason, Message: fmt.Sprintf("%s skipped: %s", path, reason)})
+}
+
+func reportFileDeleted(path string) {
+	emit(event{Type: eventFileDeleted, Path: path, Message: fmt.Sprintf("%s deleted.", path)})
+}
+
+func reportScriptSet(path, name, value string) {
+	emit(event{Type: eventScriptSet, Path: path, Script: name, Value: value, Message: fmt.Sprintf("'%s' script added/updated in %s.", name, path)})
+}
+
+func reportScriptRemoved(path, name string) {
+	emit(event{Type: eventScriptRemoved, Path: path, Script: name, Message: fmt.Sprintf("'%s' script removed from %s.", name, path)})
+}
+
+func reportHookUpdated(path, line, message string) {
+	emit(event{Type: eventHookUpdated, Path: path, Value: line, Message: message})
+}
+
+// runCommand runs an external command, reporting its start and its exit code.
+// what describes the command in text mode, e.g. "Installing Husky".
+func runCommand(args []string, what string) error {
+	emit(event{Type: eventCommandStarted, Command: args, Message: fmt.Sprintf("%s: %s", what, strings.Join(args, " "))})
+
+	command := exec.Command(args[0], args[1:]...)
+	command.Stdin = os.Stdin
+	command.Stdout = commandOutput()
+	command.Stderr = os.Stderr
+	err := command.Run()
+
+	exitCode := 0
+	message := fmt.Sprintf("%s: done.", what)
+	if err != nil {
+		exitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		}
+		message = fmt.Sprintf("%s failed: %v", what, err)
+	}
+	emit(event{Type: eventCommandFinished, Command: args, ExitCode: &exitCode, Message: message})
+	return err
+}
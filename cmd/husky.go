@@ -4,10 +4,6 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"fmt"
-	"os"
-	"os/exec"
-
 	"github.com/spf13/cobra"
 )
 
@@ -20,81 +16,48 @@ var huskyCmd = &cobra.Command{
 It detects your package manager (pnpm, npm, yarn, or bun), installs Husky,
 and runs the initialization command to set up the .husky directory and the
 prepare script in package.json.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		setupHusky()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setupHusky()
 	},
 }
 
-func setupHusky() {
-	fmt.Println("Setting up Husky...")
-
-	// Define package managers and their commands
-	// Note: Yarn init is different, skipping for now based on docs
-	packageManagers := []struct {
-		name       string
-		installCmd []string
-		initCmd    []string
-	}{
-		{"pnpm", []string{"pnpm", "add", "--save-dev", "husky"}, []string{"pnpm", "exec", "husky", "init"}},
-		{"npm", []string{"npm", "install", "--save-dev", "husky"}, []string{"npx", "husky", "init"}},
-		// {"yarn", []string{"yarn", "add", "--dev", "husky"}, []string{"yarn", "husky", "init"}}, // Yarn init requires manual steps
-		{"bun", []string{"bun", "add", "--dev", "husky"}, []string{"bunx", "husky", "init"}},
-	}
-
-	var foundPackageManager string
-	var installCmdArgs []string
-	var initCmdArgs []string
+// huskyInstalls lists what setup husky installs.
+func huskyInstalls(workspacePlan) []installRequest {
+	return []installRequest{{What: "Husky", Packages: []string{"husky"}}}
+}
 
-	// Check for package managers in order
-	for _, pm := range packageManagers {
-		// Check if the package manager command exists
-		_, err := exec.LookPath(pm.name)
-		if err == nil {
-			foundPackageManager = pm.name
-			installCmdArgs = pm.installCmd
-			initCmdArgs = pm.initCmd
-			fmt.Printf("Found package manager: %s\n", foundPackageManager)
-			break // Use the first one found
-		}
-	}
+func setupHusky() error {
+	currentTool = HUSKY
+	reportInfo("Setting up Husky...")
 
-	if foundPackageManager == "" {
-		fmt.Println("Error: No supported package manager (pnpm, npm, yarn, bun) found.")
-		fmt.Println("Please install one of these package managers and try again.")
-		return
+	pm, found := detectPackageManager()
+	if !found {
+		return errNoPackageManager
 	}
+	foundPackageManager := pm.Name
+	// Note: Yarn init is different, it is skipped below based on docs
+	initCmdArgs := pm.ExecArgs("husky", "init")
 
 	// 1. Install Husky
-	fmt.Printf("Running installation command: %s %v\n", installCmdArgs[0], installCmdArgs[1:])
-	installCmd := exec.Command(installCmdArgs[0], installCmdArgs[1:]...)
-	installCmd.Stdout = os.Stdout
-	installCmd.Stderr = os.Stderr
-	err := installCmd.Run()
+	err := runInstalls(pm, huskyInstalls(workspacePlan{}))
 	if err != nil {
-		fmt.Printf("Error installing Husky with %s: %v\n", foundPackageManager, err)
-		return
+		return err
 	}
-	fmt.Println("Husky installed successfully.")
 
 	// 2. Run husky init
 	// Special handling for yarn init if we decide to add it later, but for now, skip.
 	if foundPackageManager == "yarn" {
-		fmt.Println("Skipping husky init for yarn. Please refer to Husky documentation for manual setup.")
-		return
+		reportWarning("Skipping husky init for yarn. Please refer to Husky documentation for manual setup.")
+		return nil
 	}
 
-	fmt.Printf("Running init command: %s %v\n", initCmdArgs[0], initCmdArgs[1:])
-	initCmd := exec.Command(initCmdArgs[0], initCmdArgs[1:]...)
-	initCmd.Stdout = os.Stdout
-	initCmd.Stderr = os.Stderr
-	err = initCmd.Run()
+	err = runCommand(initCmdArgs, "Initializing Husky")
 	if err != nil {
-		fmt.Printf("Error running husky init with %s: %v\n", foundPackageManager, err)
-		return
+		return &configError{Path: ".husky", Err: err}
 	}
-	fmt.Println("Husky initialized successfully.")
 
-	fmt.Println("Husky setup complete.")
+	reportInfo("Husky setup complete.")
+	return nil
 }
 
 func init() {
@@ -4,39 +4,68 @@ Copyright © 2024 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"cmp"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/CrossEvol/setup/assets"
+	"github.com/CrossEvol/setup/common"
 	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
-	"io"
-	"log"
-	"net/http"
+	"maps"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 )
 
 // gitignoreCmd represents the gitignore command
 var gitignoreCmd = &cobra.Command{
-	Use:   "ignore [lang]? [--all]? [--char]?",
+	Use:   "ignore [template...] [--all]? [--char]?",
 	Short: "Generate a .gitignore file for a specified language or framework",
-	Long: `This command is used to generate a .gitignore file for a specified language or framework.
-It fetches the .gitignore template from GitHub's collection of .gitignore files and saves it locally.
-For example, you can use this command to quickly set up a .gitignore file for your Go projects.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		data := assets.GitignorePairs
+	Long: `This command is used to generate a .gitignore file for the specified languages or frameworks.
+The templates of GitHub's collection of .gitignore files are embedded in the binary, so it works
+without network access; --remote fetches the latest version of the template from GitHub instead.
+'setup ignore sync' refreshes the list of templates for those added since the release.
+For example, you can use this command to quickly set up a .gitignore file for your Go projects.
 
-		var gitignorePairs map[string]string
-		if err := json.Unmarshal(data, &gitignorePairs); err != nil {
-			log.Fatalf("Failed to parse JSON: %v", err)
+Each template is written to .gitignore as a block between marker comments
+("# >>> setup:Go" ... "# <<< setup:Go"). New blocks are appended and existing
+blocks replaced, so lines written by hand are kept. Patterns already listed
+above a block are left out of it:
+
+  setup ignore Go Node Global/macOS Global/JetBrains
+
+Without templates a filterable picker lists all of them, with the templates of
+the ecosystems found in the current directory (go.mod, package.json, Cargo.toml,
+.idea/ and so on) already checked. --auto applies those templates without asking.
+
+With --stdout the result is printed instead of written to .gitignore.
+
+Fetched templates are cached in the user cache directory and revalidated with
+their ETag; --refresh downloads them again. --base-url or $SETUP_GITIGNORE_BASE_URL
+points at a mirror of github/gitignore, e.g. on GitHub Enterprise or a file server.
+
+--source name=location (repeatable, or comma-separated in $SETUP_GITIGNORE_SOURCES)
+adds the templates of a team as name/<template>, e.g. acme/Service. The location is
+a directory of *.gitignore files, a git repository (git+<url> or ending in .git,
+cloned into the user cache) or an http(s) URL of a JSON index mapping keys to
+paths relative to it. A name without prefix is looked up in github/gitignore
+first, then in the sources in the order given; sources sharing a name are
+merged, the first one winning for templates they both have.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		gitignorePairs, err := loadGitignorePairs()
+		if err != nil {
+			return err
 		}
 
 		// Check for flags
 		all, _ := cmd.Flags().GetBool("all")
 		char, _ := cmd.Flags().GetString("char")
+		remote, _ := cmd.Flags().GetBool("remote")
 
 		// list all options
 		if all {
@@ -45,7 +74,7 @@ For example, you can use this command to quickly set up a .gitignore file for yo
 			for key := range gitignorePairs {
 				fmt.Println(key)
 			}
-			return
+			return nil
 		}
 
 		// list all options with prefix letter
@@ -57,115 +86,829 @@ For example, you can use this command to quickly set up a .gitignore file for yo
 					fmt.Println(key)
 				}
 			}
-			return
+			return nil
 		}
 
-		// choose the language with the prefix letter
-		if len(args) == 0 {
-			fmt.Println("=====================================================>")
+		if (remote || refreshTemplates) && offlineMode {
+			return fmt.Errorf("cannot fetch the latest templates: %w", errOffline)
+		}
 
-			var letters []string
-			for i := 65; i < 91; i++ {
-				ch := string(uint8(i))
-				letters = append(letters, ch)
-				letters = append(letters, strings.ToLower(ch))
-			}
-
-			var ch string
-
-			form := huh.NewForm(
-				huh.NewGroup(
-					huh.NewInput().
-						Value(&ch).
-						Title("Input the first letter: [A-Za-z]").
-						Placeholder("A").
-						Validate(func(s string) error {
-							if !slices.Contains(letters, ch) {
-								return errors.New("unknown Character")
-							}
-							return nil
-						}).
-						Description("Then it will list all options start with it."),
-				),
-			)
-
-			err := form.Run()
+		// apply the templates recommended for the project
+		detected := common.DetectEcosystems(".")
+		auto, _ := cmd.Flags().GetBool("auto")
+		if auto {
+			if len(detected) == 0 {
+				return errors.New("no known ecosystem found in the current directory")
+			}
+			for _, ecosystem := range detected {
+				reportInfo("Detected %s (%s)", ecosystem.Name, ecosystem.Marker)
+			}
+			for _, template := range common.RecommendedGitignoreTemplates(detected) {
+				if !slices.Contains(args, template) {
+					args = append(args, template)
+				}
+			}
+		}
 
+		// choose the templates in the picker
+		if len(args) == 0 {
+			selection, err := pickGitignoreTemplates(gitignorePairs, common.RecommendedGitignoreTemplates(detected))
 			if err != nil {
-				fmt.Println("Uh oh:", err)
-				os.Exit(1)
+				return err
 			}
+			if len(selection) == 0 {
+				reportInfo("No template chosen.")
+				return nil
+			}
+			args = selection
+		}
 
-			var options []huh.Option[string]
-			for key := range gitignorePairs {
-				if strings.ToLower(key)[:1] == strings.ToLower(ch) {
-					option := huh.NewOption(key, fmt.Sprintf("%s", key))
-					options = append(options, option)
-				}
+		// pass the target languages
+		file, err := readGitignore(gitignoreFile)
+		if err != nil {
+			return err
+		}
+		for _, arg := range args {
+			key, path, ok := gitignoreTemplate(gitignorePairs, arg)
+			if !ok {
+				return fmt.Errorf("no matching key found for: %s", arg)
+			}
+			content, err := templateContent(key, path, remote)
+			if err != nil {
+				return err
 			}
+			file.SetBlock(key, string(content))
+		}
 
-			sort.Slice(options, func(i, j int) bool {
-				return strings.Compare(options[i].Key, options[j].Key) < 0
-			})
+		stdout, _ := cmd.Flags().GetBool("stdout")
+		if stdout {
+			fmt.Print(file.String())
+			return nil
+		}
+		return writeFile(gitignoreFile, []byte(file.String()), 0644)
+	},
+}
 
-			var selection string
-			form = huh.NewForm(
-				huh.NewGroup(
-					huh.NewSelect[string]().Title("Choose the Lang:").
-						Options(
-							options...,
-						).
-						Description("Download the .gitignore for the language").
-						Value(&selection),
-				),
-			)
+// ignoreUpdateCmd represents the ignore update command
+var ignoreUpdateCmd = &cobra.Command{
+	Use:   "update [file]",
+	Short: "Refresh the blocks managed by setup ignore to the current templates",
+	Long: `Refresh every block between "# >>> setup:<template>" and "# <<< setup:<template>"
+of .gitignore (or the given file) to the current version of its template and
+print what changed. Lines written by hand are left untouched. With --dry-run the
+changes are only printed.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		remote, _ := cmd.Flags().GetBool("remote")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		path := gitignoreFile
+		if len(args) == 1 {
+			path = args[0]
+		}
 
-			err = form.Run()
+		gitignorePairs, err := loadGitignorePairs()
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		file := common.ParseGitignore(string(data))
+
+		applied := file.Applied()
+		if len(applied) == 0 {
+			reportInfo("%s has no blocks managed by setup ignore.", path)
+			return nil
+		}
 
+		changed := false
+		for _, template := range applied {
+			key, templatePath, ok := gitignoreTemplate(gitignorePairs, template)
+			if !ok {
+				reportWarning("unknown template %s, its block is kept as it is", template)
+				continue
+			}
+			content, err := templateContent(key, templatePath, remote)
 			if err != nil {
-				fmt.Println("Uh oh:", err)
-				os.Exit(1)
+				return err
 			}
 
-			download(gitignorePairs[selection])
-			return
+			before, _ := file.Block(template)
+			file.SetBlock(template, string(content))
+			after, _ := file.Block(template)
+			if diff := common.DiffLines(before, after); len(diff) > 0 {
+				changed = true
+				reportInfo("@@ setup:%s @@\n%s", template, strings.Join(diff, "\n"))
+			}
 		}
 
-		// pass the target language
-		targetKey := strings.ToLower(args[0])
-		for key, value := range gitignorePairs {
-			if strings.ToLower(key) == targetKey {
-				download(value)
-				return
+		if !changed {
+			reportInfo("All blocks of %s are up to date.", path)
+			return nil
+		}
+		if dryRun {
+			return nil
+		}
+		return writeFile(path, []byte(file.String()), 0644)
+	},
+}
+
+// ignoreListAppliedCmd represents the ignore list-applied command
+var ignoreListAppliedCmd = &cobra.Command{
+	Use:   "list-applied [file]",
+	Short: "List the templates applied to .gitignore by setup ignore",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := gitignoreFile
+		if len(args) == 1 {
+			path = args[0]
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, template := range common.ParseGitignore(string(data)).Applied() {
+			fmt.Println(template)
+		}
+		return nil
+	},
+}
+
+// ignoreSyncCmd represents the ignore sync command
+var ignoreSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Refresh the list of github/gitignore templates",
+	Long: `List the templates of github/gitignore through the GitHub API and keep the list
+in the user cache, where it replaces the one embedded in the binary, so templates
+added since the release show up in --all, --char and the picker. Their content is
+fetched on first use.
+
+$GITHUB_TOKEN is sent when set, raising the rate limit of the API from 60 requests
+an hour. --api-url or $GITHUB_API_URL points at GitHub Enterprise.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if offlineMode {
+			return fmt.Errorf("cannot list the templates: %w", errOffline)
+		}
+		index := common.NewGitignoreIndex()
+		if apiURL, _ := cmd.Flags().GetString("api-url"); apiURL != "" {
+			index.APIURL = apiURL
+		}
+		return syncGitignoreIndex(index)
+	},
+}
+
+// syncGitignoreIndex fetches the index and writes it into the user cache.
+func syncGitignoreIndex(index *common.GitignoreIndex) error {
+	previous, err := baseGitignorePairs()
+	if err != nil {
+		return err
+	}
+	pairs, err := index.Fetch()
+	if err != nil {
+		return err
+	}
+	if len(pairs) == 0 {
+		return fmt.Errorf("no templates found in %s", index.Repo)
+	}
+
+	cache, err := common.GitignoreIndexCache()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(pairs, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(cache), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(cache, data, 0644); err != nil {
+		return err
+	}
+
+	added, removed := 0, 0
+	for key := range pairs {
+		if _, ok := previous[key]; !ok {
+			added++
+		}
+	}
+	for key := range previous {
+		if _, ok := pairs[key]; !ok {
+			removed++
+		}
+	}
+	reportInfo("%d templates (%d added, %d removed) written to %s", len(pairs), added, removed, cache)
+	return nil
+}
+
+// ignoreDeriveCmd represents the ignore derive command
+var ignoreDeriveCmd = &cobra.Command{
+	Use:   "derive --for prettier,docker,eslint,npm",
+	Short: "Derive the ignore files of other tools from .gitignore",
+	Long: `Write the patterns of .gitignore, followed by the defaults each tool needs, into:
+
+  prettier  .prettierignore (adds build, coverage, .next, node_modules)
+  docker    .dockerignore (adds .git, Dockerfile* and node_modules; patterns
+            without a slash get a leading **/ since Docker anchors them)
+  eslint    the ignores array of eslint.config.mjs (adds dist, build, coverage)
+  npm       .npmignore, which npm reads instead of .gitignore (adds .github,
+            .husky, coverage and the like). Patterns that would leave out the
+            main, module, types, bin or files of package.json are skipped, as
+            build output is usually ignored by git but published.
+
+The patterns are kept in blocks between "# >>> setup:" markers (comments in
+eslint.config.mjs), so running it again after changing .gitignore updates them
+and keeps the lines written by hand.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tools, _ := cmd.Flags().GetStringSlice("for")
+		if len(tools) == 0 {
+			return errors.New("choose the tools with --for, e.g. --for prettier,docker")
+		}
+		for _, tool := range tools {
+			if _, ok := ignoreTargets[tool]; !ok {
+				return fmt.Errorf("unknown tool %s, want one of %s", tool, strings.Join(slices.Sorted(maps.Keys(ignoreTargets)), ", "))
 			}
 		}
+		if _, err := os.Stat(gitignoreFile); os.IsNotExist(err) {
+			reportWarning("no %s found, only the defaults of the tools are written", gitignoreFile)
+		}
 
-		log.Printf("No matching key found for: %s", targetKey)
+		var errs []error
+		for _, tool := range tools {
+			errs = append(errs, deriveIgnore(".", tool))
+		}
+		return errors.Join(errs...)
 	},
 }
 
-func download(value string) {
-	url := fmt.Sprintf("https://raw.githubusercontent.com/github/gitignore/main/%s", value)
-	resp, err := http.Get(url)
+// ignoreTarget is a tool whose ignore patterns setup ignore derive keeps in
+// sync with .gitignore.
+type ignoreTarget struct {
+	File      string              // the ignore file, or the config holding the patterns
+	Extras    []string            // patterns the tool needs besides those of .gitignore
+	Translate func(string) string // rewrites a .gitignore pattern, nil when the syntax is the same
+}
+
+// ignoreTargets are the tools of setup ignore derive.
+var ignoreTargets = map[string]ignoreTarget{
+	PRETTIER: {File: prettierIgnoreFile, Extras: []string{"build", "coverage", ".next", "node_modules"}},
+	"docker": {
+		File:      ".dockerignore",
+		Extras:    []string{".git", ".dockerignore", "Dockerfile*", "**/node_modules", "**/npm-debug.log"},
+		Translate: common.AnchoredGlob,
+	},
+	ESLINT: {File: eslintConfigFile, Extras: []string{"**/dist/", "**/build/", "**/coverage/"}, Translate: common.AnchoredGlob},
+	"npm":  {File: ".npmignore", Extras: []string{".github/", ".husky/", ".changeset/", ".vscode/", "coverage/", ".env*"}},
+}
+
+// Markers around the ignores written into eslint.config.mjs.
+const (
+	eslintIgnoresStart = "// >>> setup:gitignore"
+	eslintIgnoresEnd   = "// <<< setup:gitignore"
+)
+
+// deriveIgnore writes the ignore patterns of a tool inside dir from the
+// .gitignore of dir and the extras of the tool, in managed blocks so a re-run
+// updates them and keeps the lines written by hand.
+func deriveIgnore(dir, tool string) error {
+	patterns, err := derivedPatterns(dir, tool)
 	if err != nil {
-		log.Fatalf("Failed to get URL: %v", err)
+		return err
+	}
+	path := filepath.Join(dir, ignoreTargets[tool].File)
+	if tool == ESLINT {
+		return setEslintIgnores(path, append(patterns, ignoreTargets[tool].Extras...))
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			log.Fatalf("Failed to read response body: %v", err)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return writeFile(path, []byte(derivedIgnoreFile("", tool, patterns)), 0644)
+	} else if err != nil {
+		return err
+	}
+	return updateFile(path, []byte(derivedIgnoreFile(string(data), tool, patterns)), 0644)
+}
+
+// derivedPatterns returns the patterns of the .gitignore inside dir, in the
+// syntax of the tool.
+func derivedPatterns(dir, tool string) ([]string, error) {
+	target := ignoreTargets[tool]
+	data, err := os.ReadFile(filepath.Join(dir, gitignoreFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var patterns []string
+	for _, pattern := range common.GitignorePatterns(string(data)) {
+		if target.Translate != nil {
+			pattern = target.Translate(pattern)
 		}
+		patterns = append(patterns, pattern)
+	}
+	if tool == "npm" {
+		patterns = publishablePatterns(dir, patterns)
+	}
+	return patterns, nil
+}
+
+// derivedIgnoreFile updates the blocks of the patterns and the extras of a
+// tool in the content of its ignore file.
+func derivedIgnoreFile(content, tool string, patterns []string) string {
+	file := common.ParseGitignore(content)
+	if _, ok := file.Block("gitignore"); ok || len(patterns) > 0 {
+		file.SetBlock("gitignore", strings.Join(patterns, "\n"))
+	}
+	file.SetBlock(tool, strings.Join(ignoreTargets[tool].Extras, "\n"))
+	return file.String()
+}
+
+// setEslintIgnores writes the patterns as a global ignores object at the
+// start of the array exported by a flat config, replacing the one written
+// before.
+func setEslintIgnores(path string, patterns []string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		reportError("reading %s: %v", path, err)
+		return &configError{Path: path, Err: err}
+	}
+	content := string(data)
+
+	var b strings.Builder
+	b.WriteString(eslintIgnoresStart + "\n  {\n    ignores: [\n")
+	var seen []string
+	for _, pattern := range patterns {
+		if !slices.Contains(seen, pattern) {
+			seen = append(seen, pattern)
+			fmt.Fprintf(&b, "      %s,\n", strconv.Quote(pattern))
+		}
+	}
+	b.WriteString("    ],\n  },\n  " + eslintIgnoresEnd)
 
-		// Write the content to a file
-		if err := os.WriteFile(value, body, 0644); err != nil {
-			log.Fatalf("Failed to write file: %v", err)
+	if start := strings.Index(content, eslintIgnoresStart); start >= 0 {
+		end := strings.Index(content[start:], eslintIgnoresEnd)
+		if end < 0 {
+			err := fmt.Errorf("%q has no matching %q", eslintIgnoresStart, eslintIgnoresEnd)
+			reportError("updating %s: %v", path, err)
+			return &configError{Path: path, Err: err}
 		}
-		fmt.Printf("Content saved to file: %s\n", value)
+		content = content[:start] + b.String() + content[start+end+len(eslintIgnoresEnd):]
+	} else if i := strings.Index(content, "export default ["); i >= 0 {
+		i += len("export default [")
+		content = content[:i] + "\n  " + b.String() + content[i:]
 	} else {
-		log.Fatalf("Failed to fetch content, status code: %d", resp.StatusCode)
+		err := errors.New(`no "export default [" to add the ignores to`)
+		reportError("updating %s: %v", path, err)
+		return &configError{Path: path, Err: err}
+	}
+	return updateFile(path, []byte(content), 0644)
+}
+
+// publishablePatterns leaves out the patterns that would keep the entry
+// points or files listed in the package.json of dir out of the npm package.
+func publishablePatterns(dir string, patterns []string) []string {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return patterns
+	}
+	var pkg struct {
+		Main    string   `json:"main"`
+		Module  string   `json:"module"`
+		Types   string   `json:"types"`
+		Typings string   `json:"typings"`
+		Bin     any      `json:"bin"`
+		Files   []string `json:"files"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return patterns
+	}
+	if len(pkg.Files) > 0 {
+		reportWarning("%s lists \"files\": npm packs only those and ignores the root .npmignore, so add the exclusions to \"files\" instead (e.g. \"!**/*.test.js\")",
+			filepath.Join(dir, "package.json"))
+	}
+	published := append([]string{pkg.Main, pkg.Module, pkg.Types, pkg.Typings}, pkg.Files...)
+	switch bin := pkg.Bin.(type) {
+	case string:
+		published = append(published, bin)
+	case map[string]any:
+		for _, file := range bin {
+			if file, ok := file.(string); ok {
+				published = append(published, file)
+			}
+		}
+	}
+
+	var kept []string
+	for _, pattern := range patterns {
+		rules := common.ParseGitignoreRules(".npmignore", pattern)
+		if len(rules) == 1 && !rules[0].Negate && ignoresPublished(&rules[0], published) {
+			continue
+		}
+		kept = append(kept, pattern)
+	}
+	return kept
+}
+
+// ignoresPublished reports whether the rule matches a published file or one
+// of its directories. Globs such as dist/** count by their literal part.
+func ignoresPublished(rule *common.GitignoreRule, published []string) bool {
+	for _, file := range published {
+		if i := strings.IndexAny(file, "*?["); i >= 0 {
+			file = file[:i]
+		}
+		file = strings.Trim(filepath.ToSlash(filepath.Clean(file)), "/")
+		if file == "" || file == "." || strings.HasPrefix(file, "..") {
+			continue
+		}
+		elems := strings.Split(file, "/")
+		for i := range elems {
+			p := strings.Join(elems[:i+1], "/")
+			if rule.Match(p, true) || (i == len(elems)-1 && rule.Match(p, false)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ignoreCheckCmd represents the ignore check command
+var ignoreCheckCmd = &cobra.Command{
+	Use:   "check <path>...",
+	Short: "Show the .gitignore rule deciding whether each path is ignored",
+	Long: `Match each path against the .gitignore files of the current directory and
+its subdirectories, with the semantics of git: negations, directory-only
+patterns, anchored paths, ** and nested .gitignore files, whose rules come
+after those of their parents. A path inside an ignored directory is ignored by
+the rule of the directory.
+
+Like 'git check-ignore -v', it prints the file, line and pattern of the rule
+deciding each path, a negated pattern meaning the path is not ignored, or
+"::" when no rule matches:
+
+  .gitignore:3:*.log	debug.log`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		matcher, err := common.LoadGitignoreMatcher(".")
+		if err != nil {
+			return err
+		}
+		for _, arg := range args {
+			rel, err := filepath.Rel(".", arg)
+			if filepath.IsAbs(arg) {
+				wd, _ := os.Getwd()
+				rel, err = filepath.Rel(wd, arg)
+			}
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return fmt.Errorf("%s is outside the current directory", arg)
+			}
+			isDir := strings.HasSuffix(arg, "/")
+			if info, err := os.Stat(arg); err == nil {
+				isDir = info.IsDir()
+			}
+
+			rule, _ := matcher.Match(filepath.ToSlash(rel), isDir)
+			if rule == nil {
+				fmt.Printf("::\t%s\n", arg)
+				continue
+			}
+			fmt.Printf("%s:%d:%s\t%s\n", rule.File, rule.Line, rule.Pattern, arg)
+		}
+		return nil
+	},
+}
+
+// ignoreLintCmd represents the ignore lint command
+var ignoreLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Report useless .gitignore patterns and tracked files they ignore",
+	Long: `Check the .gitignore files of the current directory and its subdirectories for:
+
+  - patterns that match nothing in the working tree
+  - patterns shadowed by earlier ones, which ignore only what is already
+    ignored or re-include nothing
+  - files tracked by git (git ls-files) that the rules would ignore
+
+As with git, the contents of ignored directories are not looked at. Templates
+list patterns for files a project may create later, so patterns matching
+nothing inside the blocks managed by setup ignore are only reported with
+--templates. It exits with an error when a problem is found.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		templates, _ := cmd.Flags().GetBool("templates")
+
+		type entry struct {
+			path  string
+			isDir bool
+		}
+		var entries []entry
+		matcher := &common.GitignoreMatcher{}
+		err := matcher.Walk(".", func(p string, isDir bool) {
+			entries = append(entries, entry{p, isDir})
+		})
+		if err != nil {
+			return err
+		}
+
+		problems := 0
+		managed := make(map[string]map[int]bool)
+		for i := range matcher.Rules {
+			rule := &matcher.Rules[i]
+			matched, effective := false, false
+			var shadow *common.GitignoreRule
+			for _, e := range entries {
+				if !rule.Match(e.path, e.isDir) {
+					continue
+				}
+				matched = true
+				if parent := matcher.IgnoredParent(e.path); parent != nil {
+					shadow = cmp.Or(shadow, parent)
+					continue
+				}
+				before, ignored := matcher.MatchBefore(e.path, e.isDir, i)
+				// An exclusion must change a path that is not ignored yet, a negation one that is
+				if ignored == rule.Negate {
+					effective = true
+					break
+				}
+				shadow = cmp.Or(shadow, before)
+			}
+			// Patterns inside an ignored directory can never apply
+			if dir := rule.LiteralDir(); !matched && dir != "" {
+				if parent, ignored := matcher.Match(dir, true); ignored {
+					matched, shadow = true, parent
+				}
+			}
+
+			switch {
+			case !matched:
+				if _, ok := managed[rule.File]; !ok {
+					managed[rule.File] = managedGitignoreLines(rule.File)
+				}
+				if !templates && managed[rule.File][rule.Line] {
+					continue
+				}
+				fmt.Printf("%s:%d: %s matches nothing\n", rule.File, rule.Line, rule.Pattern)
+			case effective:
+				continue
+			case shadow != nil:
+				fmt.Printf("%s:%d: %s is shadowed by %s:%d: %s\n", rule.File, rule.Line, rule.Pattern, shadow.File, shadow.Line, shadow.Pattern)
+			default:
+				fmt.Printf("%s:%d: %s re-includes nothing that is ignored\n", rule.File, rule.Line, rule.Pattern)
+			}
+			problems++
+		}
+
+		out, err := exec.Command("git", "ls-files", "-z").Output()
+		if err != nil {
+			reportWarning("git ls-files failed, tracked files are not checked: %v", err)
+		}
+		for _, file := range strings.Split(strings.TrimRight(string(out), "\x00"), "\x00") {
+			if file == "" {
+				continue
+			}
+			if rule, ignored := matcher.Match(file, false); ignored {
+				fmt.Printf("%s is tracked but ignored by %s:%d: %s\n", file, rule.File, rule.Line, rule.Pattern)
+				problems++
+			}
+		}
+
+		if problems > 0 {
+			return fmt.Errorf("%d problems found in the .gitignore files", problems)
+		}
+		reportInfo("No problems found in the .gitignore files.")
+		return nil
+	},
+}
+
+// managedGitignoreLines returns the line numbers inside the blocks managed
+// by setup ignore in a .gitignore.
+func managedGitignoreLines(file string) map[int]bool {
+	lines := make(map[int]bool)
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return lines
+	}
+	inside := false
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, common.GitignoreBlockStart):
+			inside = true
+		case strings.HasPrefix(line, common.GitignoreBlockEnd):
+			inside = false
+		case inside:
+			lines[i+1] = true
+		}
+	}
+	return lines
+}
+
+// gitignoreGroup orders the templates in the picker: the top-level language
+// and framework templates first, then those of the extra sources, Global/
+// (editors and operating systems) and community/.
+func gitignoreGroup(key string) int {
+	_, private := privateTemplates[key]
+	switch {
+	case private:
+		return 1
+	case strings.HasPrefix(key, "Global/"):
+		return 2
+	case strings.HasPrefix(key, "community/"):
+		return 3
+	default:
+		return 0
+	}
+}
+
+// pickGitignoreTemplates lets the user filter and choose any number of
+// templates; the preselected ones start checked.
+func pickGitignoreTemplates(gitignorePairs map[string]string, preselected []string) ([]string, error) {
+	keys := make([]string, 0, len(gitignorePairs))
+	for key := range gitignorePairs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if gi, gj := gitignoreGroup(keys[i]), gitignoreGroup(keys[j]); gi != gj {
+			return gi < gj
+		}
+		return strings.ToLower(keys[i]) < strings.ToLower(keys[j])
+	})
+
+	options := make([]huh.Option[string], 0, len(keys))
+	for _, key := range keys {
+		options = append(options, huh.NewOption(key, key).Selected(slices.Contains(preselected, key)))
+	}
+
+	var selection []string
+	err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().Title("Choose the templates:").
+				Options(options...).
+				Description("Type / to filter, e.g. \"jet\" for Global/JetBrains. Templates of the detected languages are checked.").
+				Filterable(true).
+				Height(20).
+				Value(&selection),
+		),
+	).Run()
+	return selection, err
+}
+
+// gitignoreFile is the file written by setup ignore.
+const gitignoreFile = `.gitignore`
+
+// baseGitignorePairs returns the template keys and paths of github/gitignore:
+// the index written by setup ignore sync if there is one, else the embedded one.
+func baseGitignorePairs() (map[string]string, error) {
+	data, source := assets.GitignorePairs, "the embedded index"
+	if cache, err := common.GitignoreIndexCache(); err == nil {
+		if cached, err := os.ReadFile(cache); err == nil {
+			data, source = cached, cache
+		}
+	}
+	var gitignorePairs map[string]string
+	if err := json.Unmarshal(data, &gitignorePairs); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON of %s: %w", source, err)
+	}
+	return gitignorePairs, nil
+}
+
+// gitignoreSourceSpecs holds the --source flags.
+var gitignoreSourceSpecs []string
+
+// gitignoreSources returns the template sources of --source, then those of
+// $SETUP_GITIGNORE_SOURCES.
+func gitignoreSources() ([]common.GitignoreSource, error) {
+	specs := slices.Clone(gitignoreSourceSpecs)
+	if env := os.Getenv(common.GitignoreSourcesEnv); env != "" {
+		specs = append(specs, strings.Split(env, ",")...)
 	}
+	var sources []common.GitignoreSource
+	for _, spec := range specs {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+		source, err := common.ParseGitignoreSource(spec)
+		if err != nil {
+			return nil, err
+		}
+		source.Offline = offlineMode
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// privateTemplates maps the keys of the templates from extra sources to their
+// source, and loadedSources lists those sources in order of precedence.
+var (
+	privateTemplates map[string]common.GitignoreSource
+	loadedSources    []common.GitignoreSource
+)
+
+// loadGitignorePairs returns the embedded template keys and their paths,
+// followed by the templates of the extra sources as <source>/<template>.
+// When sources share a name, the one given first wins.
+func loadGitignorePairs() (map[string]string, error) {
+	gitignorePairs, err := baseGitignorePairs()
+	if err != nil {
+		return nil, err
+	}
+
+	sources, err := gitignoreSources()
+	if err != nil {
+		return nil, err
+	}
+	privateTemplates = make(map[string]common.GitignoreSource)
+	loadedSources = nil
+	for _, source := range sources {
+		// Pulled once per command, the templates are then read from the clone
+		if err := source.Update(); err != nil {
+			reportWarning("%v, using the templates cloned before", err)
+		}
+		templates, err := source.Templates()
+		if err != nil {
+			// The other sources and github/gitignore stay usable
+			reportWarning("%v, its templates are left out", err)
+			continue
+		}
+		loadedSources = append(loadedSources, source)
+		for name, path := range templates {
+			key := source.Name + "/" + name
+			if _, ok := gitignorePairs[key]; ok {
+				continue
+			}
+			gitignorePairs[key] = path
+			privateTemplates[key] = source
+		}
+	}
+	return gitignorePairs, nil
+}
+
+// readGitignore parses a .gitignore, which may not exist yet.
+func readGitignore(path string) (*common.GitignoreFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return common.ParseGitignore(string(data)), nil
+}
+
+// gitignoreTemplate looks a template up by its key, ignoring case and an
+// optional .gitignore suffix, and returns its key and path. A name without
+// the source prefix falls back to the templates of the extra sources, in the
+// order they were given; github/gitignore comes first.
+func gitignoreTemplate(gitignorePairs map[string]string, name string) (string, string, bool) {
+	target := strings.TrimSuffix(name, ".gitignore")
+	for key, value := range gitignorePairs {
+		if strings.EqualFold(key, target) {
+			return key, value, true
+		}
+	}
+	for _, source := range loadedSources {
+		for key, value := range gitignorePairs {
+			if privateTemplates[key].Name == source.Name && strings.EqualFold(key, source.Name+"/"+target) {
+				return key, value, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// gitignoreTemplates returns the embedded templates, a fixture in the tests.
+var gitignoreTemplates = common.GitignoreTemplates
+
+// gitignoreBaseURL and refreshTemplates hold the --base-url and --refresh flags.
+var (
+	gitignoreBaseURL string
+	refreshTemplates bool
+)
+
+// templateContent returns the content of a template by its key and path:
+// from its source for the templates of extra sources, otherwise from the
+// embedded bundle, unless --remote or --refresh asks for the mirror, then from
+// the mirror through the cache.
+func templateContent(key, path string, remote bool) ([]byte, error) {
+	if source, ok := privateTemplates[key]; ok {
+		return source.Content(path, refreshTemplates)
+	}
+	if !remote && !refreshTemplates {
+		templates, err := gitignoreTemplates()
+		if err != nil {
+			return nil, err
+		}
+		if content, ok := templates[path]; ok {
+			return []byte(content), nil
+		}
+	}
+
+	fetcher := common.NewGitignoreFetcher(common.GitignoreBaseURL(gitignoreBaseURL))
+	if offlineMode {
+		if content, ok := fetcher.Cached(path); ok && !refreshTemplates {
+			return content, nil
+		}
+		return nil, fmt.Errorf("fetching %s: %w", path, errOffline)
+	}
+	return fetcher.Fetch(path, refreshTemplates)
 }
 
 func init() {
@@ -174,6 +917,23 @@ func init() {
 	// Define flags
 	gitignoreCmd.Flags().BoolP("all", "a", false, "Output all available keys")
 	gitignoreCmd.Flags().StringP("char", "c", "", "Output keys starting with a specific character")
+	gitignoreCmd.PersistentFlags().Bool("remote", false, "Fetch the latest templates from GitHub instead of the embedded ones")
+	gitignoreCmd.PersistentFlags().BoolVar(&refreshTemplates, "refresh", false, "Download the templates again, bypassing the embedded ones and the cache")
+	gitignoreCmd.PersistentFlags().StringArrayVar(&gitignoreSourceSpecs, "source", nil, "Add a template source as name=location (directory, git repository or index URL)")
+	gitignoreCmd.PersistentFlags().StringVar(&gitignoreBaseURL, "base-url", "", "Base URL of the github/gitignore mirror (default $"+common.GitignoreBaseURLEnv+" or GitHub)")
+	gitignoreCmd.Flags().Bool("stdout", false, "Print the merged .gitignore instead of writing it")
+	gitignoreCmd.Flags().Bool("auto", false, "Apply the templates recommended for the ecosystems found in the project")
+
+	gitignoreCmd.AddCommand(ignoreUpdateCmd)
+	gitignoreCmd.AddCommand(ignoreListAppliedCmd)
+	gitignoreCmd.AddCommand(ignoreCheckCmd)
+	gitignoreCmd.AddCommand(ignoreSyncCmd)
+	gitignoreCmd.AddCommand(ignoreDeriveCmd)
+	gitignoreCmd.AddCommand(ignoreLintCmd)
+	ignoreUpdateCmd.Flags().Bool("dry-run", false, "Only print the changes")
+	ignoreSyncCmd.Flags().String("api-url", "", "Base URL of the GitHub API (default $GITHUB_API_URL or https://api.github.com)")
+	ignoreDeriveCmd.Flags().StringSlice("for", nil, "Tools to derive the ignores of: prettier, docker, eslint, npm")
+	ignoreLintCmd.Flags().Bool("templates", false, "Also report patterns of the managed template blocks that match nothing")
 
 	// Here you will define your flags and configuration settings.
 
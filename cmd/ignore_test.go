@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/CrossEvol/setup/common"
+)
+
+// useTemplateBundle makes templates the embedded bundle for the test.
+func useTemplateBundle(t *testing.T, templates map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(writer).Encode(templates); err != nil {
+		t.Fatal(err)
+	}
+	writer.Close()
+
+	gitignoreTemplates = func() (map[string]string, error) {
+		return common.ParseGitignoreBundle(buf.Bytes())
+	}
+	t.Cleanup(func() { gitignoreTemplates = common.GitignoreTemplates })
+}
+
+func TestTemplateContentOffline(t *testing.T) {
+	useTemplateBundle(t, map[string]string{"Go.gitignore": "*.test\n*.out\n"})
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	offlineMode = true
+	defer func() { offlineMode = false }()
+
+	pairs, err := loadGitignorePairs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, path, ok := gitignoreTemplate(pairs, "go")
+	if !ok {
+		t.Fatal("no template Go")
+	}
+	content, err := templateContent(key, path, false)
+	if errors.Is(err, errOffline) {
+		t.Fatalf("Go needed the network: %v", err)
+	}
+	if err != nil || string(content) != "*.test\n*.out\n" {
+		t.Fatalf("templateContent(Go) = %q, %v", content, err)
+	}
+
+	// Not in the bundle and not cached
+	key, path, ok = gitignoreTemplate(pairs, "node")
+	if !ok {
+		t.Fatal("no template Node")
+	}
+	if _, err := templateContent(key, path, false); !errors.Is(err, errOffline) {
+		t.Errorf("templateContent(Node) = %v, want errOffline", err)
+	}
+}
+
+func TestSyncGitignoreIndexOverridesEmbedded(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tree":[{"path":"Go.gitignore","type":"blob"},{"path":"Brand-New.gitignore","type":"blob"}]}`))
+	}))
+	defer server.Close()
+
+	index := &common.GitignoreIndex{APIURL: server.URL, Repo: "github/gitignore", Ref: "main", Client: server.Client()}
+	if err := syncGitignoreIndex(index); err != nil {
+		t.Fatal(err)
+	}
+	pairs, err := loadGitignorePairs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 2 || pairs["Brand-New"] != "Brand-New.gitignore" {
+		t.Errorf("pairs after sync = %v", pairs)
+	}
+}
+
+// captureEvents collects the events of the test in text mode.
+func captureEvents(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var out bytes.Buffer
+	sink := eventSink
+	eventSink = &out
+	t.Cleanup(func() { eventSink = sink })
+	return &out
+}
+
+func TestDeriveIgnoreReportsUpdates(t *testing.T) {
+	t.Chdir(t.TempDir())
+	os.WriteFile(gitignoreFile, []byte("node_modules\n/dist\n"), 0644)
+	os.WriteFile(eslintConfigFile, []byte("export default [\n  js.configs.recommended,\n];\n"), 0644)
+	out := captureEvents(t)
+
+	if err := deriveIgnore(".", ESLINT); err != nil {
+		t.Fatal(err)
+	}
+	if err := deriveIgnore(".", "docker"); err != nil {
+		t.Fatal(err)
+	}
+	if err := deriveIgnore(".", "docker"); err != nil {
+		t.Fatal(err)
+	}
+	want := eslintConfigFile + " updated.\n.dockerignore created successfully.\n.dockerignore updated.\n"
+	if out.String() != want {
+		t.Errorf("events = %q, want %q", out.String(), want)
+	}
+
+	data, _ := os.ReadFile(eslintConfigFile)
+	for _, pattern := range []string{`"**/node_modules"`, `"dist"`, `"**/coverage/"`} {
+		if !strings.Contains(string(data), pattern) {
+			t.Errorf("%s is missing %s:\n%s", eslintConfigFile, pattern, data)
+		}
+	}
+}
+
+func TestDeriveNpmignoreWarnsAboutFiles(t *testing.T) {
+	tests := []struct {
+		name, packageJSON string
+		warned            bool
+	}{
+		{"files", `{"name": "demo", "main": "dist/index.js", "files": ["dist"]}`, true},
+		{"no files", `{"name": "demo", "main": "dist/index.js"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			os.WriteFile(gitignoreFile, []byte("dist/\n*.log\n"), 0644)
+			os.WriteFile("package.json", []byte(tt.packageJSON), 0644)
+			out := captureEvents(t)
+
+			if err := deriveIgnore(".", "npm"); err != nil {
+				t.Fatal(err)
+			}
+			if warned := strings.Contains(out.String(), `Warning: package.json lists "files"`); warned != tt.warned {
+				t.Errorf("warned = %v, want %v:\n%s", warned, tt.warned, out.String())
+			}
+			// dist/ holds the entry point, so it stays publishable either way
+			data, _ := os.ReadFile(".npmignore")
+			if patterns := common.GitignorePatterns(string(data)); patterns[0] != "*.log" {
+				t.Errorf(".npmignore patterns = %q", patterns)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/CrossEvol/setup/common"
+)
+
+// installRequest lists the dev dependencies a tool installs into one place.
+type installRequest struct {
+	What     string
+	Packages []string
+	Exact    bool
+	Target   *common.WorkspacePackage // nil for the project, the workspace root in a monorepo
+}
+
+func (r installRequest) targetDir() string {
+	if r.Target == nil {
+		return "."
+	}
+	return r.Target.Dir
+}
+
+// installRequests returns where a tool installs its packages under the plan:
+// at the root when it holds the config, and in every package with its own.
+func (p workspacePlan) installRequests(what string, packages []string, exact bool) []installRequest {
+	var requests []installRequest
+	if p.atRoot() {
+		requests = append(requests, installRequest{What: what, Packages: packages, Exact: exact})
+	}
+	if p.Mode == workspacePackages {
+		for i := range p.Packages {
+			requests = append(requests, installRequest{What: what, Packages: packages, Exact: exact, Target: &p.Packages[i]})
+		}
+	}
+	return requests
+}
+
+// batchedInstalls holds the packages installed up front by 'setup node' with
+// the result of their install, keyed by batchKey.
+var batchedInstalls map[string]error
+
+func batchKey(dir, name string) string {
+	return dir + "\x00" + name
+}
+
+// runInstalls installs the requests of a tool, leaving out the packages the
+// batched install of 'setup node' already took care of.
+func runInstalls(pm common.PackageManager, requests []installRequest) error {
+	var errs []error
+	for _, request := range requests {
+		var pending []string
+		var batchErr error
+		for _, name := range request.Packages {
+			err, batched := batchedInstalls[batchKey(request.targetDir(), name)]
+			if !batched {
+				pending = append(pending, name)
+			} else if err != nil {
+				batchErr = err
+			}
+		}
+		// A failed batch fails every tool it installed for, without retrying
+		errs = append(errs, batchErr)
+
+		if len(pending) == 0 {
+			if batchErr == nil {
+				reportInfo("%s already installed.", request.What)
+			}
+			continue
+		}
+		errs = append(errs, runInstall(pm, pm.AddDevArgs(versionedPackages(pending), request.Exact, request.Target), request.What))
+	}
+	return errors.Join(errs...)
+}
+
+// installBatch merges the requests of several tools into one deduplicated
+// install per target and runs them, recording the results for runInstalls.
+// Packages saved with an exact version need a command of their own.
+func installBatch(pm common.PackageManager, requests []installRequest) {
+	type group struct {
+		target   *common.WorkspacePackage
+		exact    bool
+		what     []string
+		packages []string
+	}
+	var groups []*group
+	byKey := make(map[string]*group)
+	seen := make(map[string]bool)
+	for _, request := range requests {
+		key := fmt.Sprintf("%s\x00%t", request.targetDir(), request.Exact)
+		g, ok := byKey[key]
+		if !ok {
+			g = &group{target: request.Target, exact: request.Exact}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		added := false
+		for _, name := range request.Packages {
+			if seen[batchKey(request.targetDir(), name)] {
+				continue
+			}
+			seen[batchKey(request.targetDir(), name)] = true
+			g.packages = append(g.packages, name)
+			added = true
+		}
+		if added {
+			g.what = append(g.what, request.What)
+		}
+	}
+
+	batchedInstalls = make(map[string]error)
+	for _, g := range groups {
+		if len(g.packages) == 0 {
+			continue
+		}
+		what := strings.Join(g.what, ", ")
+		dir := "."
+		if g.target != nil {
+			what += " in " + g.target.Name
+			dir = g.target.Dir
+		}
+		err := runInstall(pm, pm.AddDevArgs(versionedPackages(g.packages), g.exact, g.target), what)
+		for _, name := range g.packages {
+			batchedInstalls[batchKey(dir, name)] = err
+		}
+	}
+}
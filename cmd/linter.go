@@ -4,10 +4,8 @@ Copyright © 2024 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"github.com/CrossEvol/setup/common"
-	"os"
-	"os/exec"
 
 	"github.com/spf13/cobra"
 )
@@ -20,36 +18,17 @@ var linterCmd = &cobra.Command{
 
 It installs ESLint, Prettier, and related plugins, creates configuration files,
 and adds lint and format scripts to your package.json. This combined setup
-ensures both code quality and consistent formatting in your project.`,
-	Run: func(cmd *cobra.Command, args []string) {
+ensures both code quality and consistent formatting in your project.
 
-		setupLinter()
+Inside a monorepo it asks whether to set both up at the workspace root, in
+each package, or both with package scripts delegating to the root config.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setupLinter()
 	},
 }
 
-func setupLinter() {
-	const eslintFile = `eslint.config.mjs`
-	const pnpmCommand = `pnpm install --save-dev eslint globals @eslint/js typescript-eslint && pnpm add --save-dev --save-exact prettier eslint-config-prettier eslint-plugin-prettier `
-	const ignoreFile = `.prettierignore`
-	const prettierFile = `.prettierrc`
-	const prettierConfig = `
-{
-    "singleQuote": true,
-    "semi": false,
-    "tabWidth": 4,
-    "plugins": []
-}
-
-`
-	const prettierIgnoreConfig = `
-# Ignore artifacts:
-build
-coverage
-.next
-node_modules
-
-`
-	const eslintConfig = `
+// linterEslintConfig is the ESLint config with the Prettier plugin written by setup linter.
+const linterEslintConfig = `
 import globals from 'globals'
 import pluginJs from '@eslint/js'
 import tseslint from 'typescript-eslint'
@@ -97,56 +76,57 @@ export default [
     prettierConfig,
 ]
 `
-	fmt.Println("linter called")
 
-	// Create eslint.config.mjs file
-	err := os.WriteFile(eslintFile, []byte(eslintConfig), 0644)
-	if err != nil {
-		fmt.Printf("Error creating %s: %v\n", eslintFile, err)
-	} else {
-		fmt.Printf("%s created successfully\n", eslintFile)
-	}
+// linterWorkspaceTitle is what setup linter asks the workspace plan for.
+const linterWorkspaceTitle = "ESLint and Prettier"
 
-	// Create .prettierignore file
-	err = os.WriteFile(ignoreFile, []byte(prettierIgnoreConfig), 0644)
-	if err != nil {
-		fmt.Printf("Error creating %s: %v\n", ignoreFile, err)
-	} else {
-		fmt.Printf("%s created successfully\n", ignoreFile)
+// linterInstalls lists what setup linter installs under the workspace plan.
+func linterInstalls(plan workspacePlan) []installRequest {
+	prettierPackages := []string{"prettier", "eslint-config-prettier", "eslint-plugin-prettier"}
+	return append(plan.installRequests("ESLint and dependencies", eslintPackages, false),
+		plan.installRequests("Prettier and related ESLint plugins", prettierPackages, true)...)
+}
+
+func setupLinter() error {
+	currentTool = "linter"
+	reportInfo("linter called")
+
+	var errs []error
+	pm, found := detectPackageManager()
+	if !found {
+		errs = append(errs, errNoPackageManager)
 	}
 
-	// Create .prettierrc file
-	err = os.WriteFile(prettierFile, []byte(prettierConfig), 0644)
+	plan, err := askWorkspacePlan(linterWorkspaceTitle)
 	if err != nil {
-		fmt.Printf("Error creating %s: %v\n", prettierFile, err)
-	} else {
-		fmt.Printf("%s created successfully\n", prettierFile)
+		return err
+	}
+	if found {
+		errs = append(errs, runInstalls(pm, linterInstalls(plan)))
 	}
 
-	// Run pnpm command
-	cmd2 := exec.Command("pnpm", "install", "--save-dev", "eslint", "globals", "@eslint/js", "typescript-eslint")
-	cmd2.Stdout = os.Stdout
-	cmd2.Stderr = os.Stderr
-	err = cmd2.Run()
-	if err != nil {
-		fmt.Printf("Error running first pnpm command: %v\n", err)
-	} else {
-		fmt.Println("ESLint and dependencies installed successfully")
+	configure := func(dir string) {
+		errs = append(errs, writeConfigFile(dir, eslintConfigFile, linterEslintConfig))
+		errs = append(errs, deriveIgnore(dir, PRETTIER))
+		errs = append(errs, writeConfigFile(dir, prettierConfigFile, prettierConfig))
+		errs = append(errs, setPackageScripts(dir, map[string]string{"lint": "eslint . --fix"}))
 	}
 
-	cmd2 = exec.Command("pnpm", "add", "--save-dev", "--save-exact", "prettier", "eslint-config-prettier", "eslint-plugin-prettier")
-	cmd2.Stdout = os.Stdout
-	cmd2.Stderr = os.Stderr
-	err = cmd2.Run()
-	if err != nil {
-		fmt.Printf("Error running second pnpm command: %v\n", err)
-	} else {
-		fmt.Println("Prettier and related ESLint plugins installed successfully")
+	// Shared config and script at the root
+	if plan.atRoot() {
+		configure(".")
 	}
 
-	newEntries := `
-		"lint": "eslint . --fix",`
-	common.UpdatePackageJSON(newEntries)
+	// Per-package configs, or package scripts delegating to the root config
+	for _, pkg := range plan.Packages {
+		if plan.Mode == workspacePackages {
+			configure(pkg.Dir)
+		} else {
+			errs = append(errs, setPackageScripts(pkg.Dir, map[string]string{"lint": fmt.Sprintf("eslint . --fix --config %s", rootConfigPath(pkg.Dir, eslintConfigFile))}))
+		}
+	}
+	errs = append(errs, plan.delegateFromRoot(pm, found, "lint"))
+	return errors.Join(errs...)
 }
 
 func init() {
@@ -4,10 +4,9 @@ Copyright © 2024 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"fmt"
+	"errors"
 	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
-	"os"
 	"slices"
 )
 
@@ -18,28 +17,33 @@ const HUSKY = "husky"
 const COMMITLINT = "commitlint"
 const LINTSTAGED = "lintStaged"
 const RELEASEIT = "releaseIt"
+const STYLELINT = "stylelint"
+const CHANGESETS = "changesets"
 
 // nodeCmd represents the node command
 var nodeCmd = &cobra.Command{
 	Use:   "node",
 	Short: "Set up Node.js project tool-chains",
 	Long: `Set up Node.js project tool-chains, include eslint, prettier, vitest, husky and so on.
-It will not only install the needed packages, but also initialize the configuration files and add corresponding scripts.`,
-	Run: func(cmd *cobra.Command, args []string) {
+It will not only install the needed packages, but also initialize the configuration files and add corresponding scripts.
+The dev dependencies of all chosen tools are installed together in one command before the tools are configured.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		var tools []string
 
-		fmt.Println("Node.js project initializing....")
+		reportInfo("Node.js project initializing....")
 		form := huh.NewForm(
 			huh.NewGroup(
 				huh.NewMultiSelect[string]().Title("Tool Chains").
 					Options(
 						huh.NewOption(ESLINT, ESLINT),
 						huh.NewOption(PRETTIER, PRETTIER),
+						huh.NewOption(STYLELINT, STYLELINT),
 						huh.NewOption(VITEST, VITEST),
 						huh.NewOption(HUSKY, HUSKY),
 						huh.NewOption(COMMITLINT, COMMITLINT),
 						huh.NewOption(LINTSTAGED, LINTSTAGED),
 						huh.NewOption(RELEASEIT, RELEASEIT),
+						huh.NewOption(CHANGESETS, CHANGESETS),
 					).
 					Description("Choose your Tools").
 					Value(&tools),
@@ -49,11 +53,10 @@ It will not only install the needed packages, but also initialize the configurat
 		err := form.Run()
 
 		if err != nil {
-			fmt.Println("Uh oh:", err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Printf("Choose tools: %s\n", tools)
+		reportInfo("Choose tools: %s", tools)
 
 		// Handle ESLint and Prettier combined setup
 		configureEslintWithPrettier := false
@@ -70,78 +73,110 @@ It will not only install the needed packages, but also initialize the configurat
 			}
 		}
 
+		var steps []nodeStep
 		if configureEslintWithPrettier {
-			fmt.Println()
-			fmt.Println("=============== Setup Eslint with Prettier BEGIN  =====================")
-			setupLinter() // Assuming setupLinter handles both
-			fmt.Println("=============== Setup Eslint with Prettier END  =====================")
-			fmt.Println()
+			steps = append(steps, nodeStep{"linter", "Eslint with Prettier", linterWorkspaceTitle, linterInstalls, setupLinter}) // Assuming setupLinter handles both
 		}
 
 		// Handle individual tool setups, skipping if combined was handled
 		if slices.Contains(tools, ESLINT) && !configureEslintWithPrettier {
-			fmt.Println()
-			fmt.Println("=============== Setup Eslint BEGIN  =====================")
-			setupEslint() // Call the specific ESLint setup
-			fmt.Println("=============== Setup Eslint END  =====================")
-			fmt.Println()
+			steps = append(steps, nodeStep{ESLINT, "Eslint", ESLINT, eslintInstalls, setupEslint}) // Call the specific ESLint setup
 		}
 
 		if slices.Contains(tools, PRETTIER) && !configureEslintWithPrettier {
-			fmt.Println()
-			fmt.Println("=============== Setup Prettier BEGIN  =====================")
-			setupPrettier() // Call the specific Prettier setup
-			fmt.Println("=============== Setup Prettier END  =====================")
-			fmt.Println()
+			steps = append(steps, nodeStep{PRETTIER, "Prettier", PRETTIER, prettierInstalls, setupPrettier}) // Call the specific Prettier setup
 		}
 
 		if slices.Contains(tools, VITEST) {
-			fmt.Println()
-			fmt.Println("=============== Setup Vitest BEGIN  =====================")
-			setupVitest()
-			fmt.Println()
-			fmt.Println("=============== Setup Vitest END  =====================")
-			fmt.Println()
+			steps = append(steps, nodeStep{VITEST, "Vitest", VITEST, vitestInstalls, setupVitest})
 		}
 
 		if slices.Contains(tools, HUSKY) {
-			fmt.Println()
-			fmt.Println("=============== Setup Husky BEGIN  =====================")
-			setupHusky()
-			fmt.Println()
-			fmt.Println("=============== Setup Husky END  =====================")
-			fmt.Println()
+			steps = append(steps, nodeStep{HUSKY, "Husky", "", huskyInstalls, setupHusky})
 		}
 
 		if slices.Contains(tools, COMMITLINT) {
-			fmt.Println()
-			fmt.Println("=============== Setup Commitlint BEGIN  =====================")
-			setupCommitlint()
-			fmt.Println()
-			fmt.Println("=============== Setup Commitlint END  =====================")
-			fmt.Println()
+			steps = append(steps, nodeStep{COMMITLINT, "Commitlint", "", commitlintInstalls, setupCommitlint})
 		}
 
 		if slices.Contains(tools, LINTSTAGED) {
-			fmt.Println()
-			fmt.Println("=============== Setup Lint-Staged BEGIN  =====================")
-			setupLintStaged()
-			fmt.Println()
-			fmt.Println("=============== Setup Lint-Staged END  =====================")
-			fmt.Println()
+			steps = append(steps, nodeStep{LINTSTAGED, "Lint-Staged", "", lintStagedInstalls, setupLintStaged})
+		}
+
+		// Stylelint runs after lint-staged so its globs can be registered there
+		if slices.Contains(tools, STYLELINT) {
+			steps = append(steps, nodeStep{STYLELINT, "Stylelint", STYLELINT, stylelintInstalls, setupStylelint})
 		}
 
 		if slices.Contains(tools, RELEASEIT) {
-			fmt.Println()
-			fmt.Println("=============== Setup Release-It BEGIN  =====================")
-			setupReleaseIt()
-			fmt.Println()
-			fmt.Println("=============== Setup Release-It END  =====================")
-			fmt.Println()
+			steps = append(steps, nodeStep{RELEASEIT, "Release-It", "", releaseItInstalls, setupReleaseIt})
+		}
+
+		if slices.Contains(tools, CHANGESETS) {
+			steps = append(steps, nodeStep{CHANGESETS, "Changesets", "", changesetsInstalls, setupChangesets})
+		}
+
+		// Install the dependencies of every tool at once, then configure them
+		if err := installNodeSteps(steps); err != nil {
+			return err
+		}
+		var results []stepResult
+		for _, step := range steps {
+			results = append(results, runStep(step.Tool, step.Title, step.Setup))
+		}
+
+		printSummary(results)
+		var errs []error
+		for _, result := range results {
+			if result.Err != nil {
+				errs = append(errs, result.Err)
+			}
+		}
+		if len(errs) > 0 {
+			return &stepsError{Failed: len(errs), Total: len(results), Err: errors.Join(errs...)}
 		}
+		return nil
 	},
 }
 
+// nodeStep is a tool set up by 'setup node'. Installs lists its dev
+// dependencies under the workspace plan asked for Workspace, which is empty
+// for tools that are only set up at the root.
+type nodeStep struct {
+	Tool      string
+	Title     string
+	Workspace string
+	Installs  func(workspacePlan) []installRequest
+	Setup     func() error
+}
+
+// installNodeSteps plans the installs of all steps, asking for their workspace
+// plans up front, and runs them as one batch. The outcome of the batch is
+// reported by the steps themselves.
+func installNodeSteps(steps []nodeStep) error {
+	pm, found := projectPackageManager()
+	if !found {
+		return nil
+	}
+
+	var requests []installRequest
+	for _, step := range steps {
+		plan := workspacePlan{Mode: workspaceRoot}
+		if step.Workspace != "" {
+			var err error
+			plan, err = askWorkspacePlan(step.Workspace)
+			if err != nil {
+				return err
+			}
+		}
+		requests = append(requests, step.Installs(plan)...)
+	}
+
+	currentTool = "node"
+	installBatch(pm, requests)
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(nodeCmd)
 
@@ -4,10 +4,8 @@ Copyright © 2024 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"os/exec"
 
 	"github.com/spf13/cobra"
 )
@@ -18,18 +16,25 @@ var prettierCmd = &cobra.Command{
 	Short: "Set up Prettier for your project",
 	Long: `This command sets up Prettier for your project.
 
-It installs Prettier, creates configuration files (.prettierrc and .prettierignore),
-and adds a format script to your package.json. Prettier helps maintain consistent
-code formatting across your project.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		setupPrettier()
+It installs Prettier, creates configuration files (.prettierrc and .prettierignore,
+derived from .gitignore as by 'setup ignore derive --for prettier'), and adds a format script to your package.json. Prettier helps maintain consistent
+code formatting across your project.
+
+Inside a monorepo it asks whether to set Prettier up at the workspace root, in
+each package, or both with package scripts delegating to the root config.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setupPrettier()
 	},
 }
 
-func setupPrettier() {
-	const ignoreFile = `.prettierignore`
-	const prettierFile = `.prettierrc`
-	const prettierConfig = `
+// Files written by setup prettier and setup linter.
+const (
+	prettierIgnoreFile = `.prettierignore`
+	prettierConfigFile = `.prettierrc`
+)
+
+// prettierConfig is the .prettierrc written by setup prettier and setup linter.
+const prettierConfig = `
 {
     "singleQuote": true,
     "semi": false,
@@ -38,7 +43,10 @@ func setupPrettier() {
 }
 
 `
-	const prettierIgnoreConfig = `
+
+// prettierIgnoreConfig is the fixed .prettierignore written before it was
+// derived from .gitignore, still recognized by setup remove.
+const prettierIgnoreConfig = `
 # Ignore artifacts:
 build
 coverage
@@ -46,102 +54,60 @@ coverage
 node_modules
 
 `
-	fmt.Println("prettier called")
 
-	// Create .prettierignore file
-	err := os.WriteFile(ignoreFile, []byte(prettierIgnoreConfig), 0644)
+// prettierIgnoreContents renders the .prettierignore setup prettier writes
+// for the current .gitignore, and the fixed one of earlier versions.
+func prettierIgnoreContents() []string {
+	patterns, err := derivedPatterns(".", PRETTIER)
 	if err != nil {
-		fmt.Printf("Error creating %s: %v\n", ignoreFile, err)
-	} else {
-		fmt.Printf("%s created successfully\n", ignoreFile)
+		return []string{prettierIgnoreConfig}
 	}
+	return []string{derivedIgnoreFile("", PRETTIER, patterns), prettierIgnoreConfig}
+}
 
-	// Create .prettierrc file
-	err = os.WriteFile(prettierFile, []byte(prettierConfig), 0644)
-	if err != nil {
-		fmt.Printf("Error creating %s: %v\n", prettierFile, err)
-	} else {
-		fmt.Printf("%s created successfully\n", prettierFile)
-	}
+// prettierInstalls lists what setup prettier installs under the workspace plan.
+func prettierInstalls(plan workspacePlan) []installRequest {
+	return plan.installRequests("Prettier", []string{"prettier"}, true)
+}
 
-	// Define package managers and their commands
-	packageManagers := []struct {
-		name       string
-		installCmd []string
-	}{
-		{"pnpm", []string{"pnpm", "add", "-D", "--save-exact", "prettier"}},
-		{"npm", []string{"npm", "install", "--save-dev", "--save-exact", "prettier"}},
-		{"yarn", []string{"yarn", "add", "--dev", "--exact", "prettier"}},
-		{"bun", []string{"bun", "add", "--dev", "--exact", "prettier"}},
-	}
+func setupPrettier() error {
+	currentTool = PRETTIER
+	reportInfo("prettier called")
 
-	var foundPackageManager string
-	var installCmdArgs []string
-
-	// Check for package managers in order
-	for _, pm := range packageManagers {
-		_, err := exec.LookPath(pm.name)
-		if err == nil {
-			foundPackageManager = pm.name
-			installCmdArgs = pm.installCmd
-			fmt.Printf("Found package manager: %s\n", foundPackageManager)
-			break // Use the first one found
-		}
+	var errs []error
+	pm, found := detectPackageManager()
+	if !found {
+		errs = append(errs, errNoPackageManager)
 	}
 
-	if foundPackageManager == "" {
-		fmt.Println("Error: No supported package manager (pnpm, npm, yarn, bun) found.")
-		fmt.Println("Please install one of these package managers and try again.")
-		// return
+	plan, err := askWorkspacePlan(PRETTIER)
+	if err != nil {
+		return err
+	}
+	if found {
+		errs = append(errs, runInstalls(pm, prettierInstalls(plan)))
 	}
 
-	// Run install command
-	if len(installCmdArgs) > 0 {
-		fmt.Printf("Running installation command: %s %v\n", installCmdArgs[0], installCmdArgs[1:])
-		installCmd := exec.Command(installCmdArgs[0], installCmdArgs[1:]...)
-		installCmd.Stdout = os.Stdout
-		installCmd.Stderr = os.Stderr
-		err = installCmd.Run()
-		if err != nil {
-			fmt.Printf("Error installing Prettier with %s: %v\n", foundPackageManager, err)
-		} else {
-			fmt.Println("Prettier installed successfully.")
-		}
-	} else if foundPackageManager != "" {
-		fmt.Printf("Package manager %s found, but no install command configured for Prettier.\n", foundPackageManager)
+	// Shared config and script at the root
+	if plan.atRoot() {
+		errs = append(errs, deriveIgnore(".", PRETTIER))
+		errs = append(errs, writeConfigFile(".", prettierConfigFile, prettierConfig))
+		errs = append(errs, setPackageScripts(".", map[string]string{"prettier": "npx prettier . --write"})) // or use detected package manager's run command
 	}
 
-	// Update package.json
-	packageJSONPath := "package.json"
-	packageJSONData, err := os.ReadFile(packageJSONPath)
-	if err != nil {
-		fmt.Printf("Error reading package.json: %v\n", err)
-	} else {
-		var pkgJSON map[string]interface{}
-		err = json.Unmarshal(packageJSONData, &pkgJSON)
-		if err != nil {
-			fmt.Printf("Error parsing package.json: %v\n", err)
+	// Per-package configs, or package scripts delegating to the root config
+	for _, pkg := range plan.Packages {
+		if plan.Mode == workspacePackages {
+			errs = append(errs, deriveIgnore(pkg.Dir, PRETTIER))
+			errs = append(errs, writeConfigFile(pkg.Dir, prettierConfigFile, prettierConfig))
+			errs = append(errs, setPackageScripts(pkg.Dir, map[string]string{"prettier": "prettier . --write"}))
 		} else {
-			scripts, ok := pkgJSON["scripts"].(map[string]interface{})
-			if !ok {
-				scripts = make(map[string]interface{})
-				pkgJSON["scripts"] = scripts
-			}
-			scripts["prettier"] = "npx prettier . --write" // or use detected package manager's run command
-
-			updatedData, err := json.MarshalIndent(pkgJSON, "", "  ")
-			if err != nil {
-				fmt.Printf("Error marshalling updated package.json: %v\n", err)
-			} else {
-				err = os.WriteFile(packageJSONPath, updatedData, 0644)
-				if err != nil {
-					fmt.Printf("Error writing updated package.json: %v\n", err)
-				} else {
-					fmt.Println("'prettier' script added/updated in package.json.")
-				}
-			}
+			errs = append(errs, setPackageScripts(pkg.Dir, map[string]string{"prettier": fmt.Sprintf("prettier . --write --config %s --ignore-path %s",
+				rootConfigPath(pkg.Dir, prettierConfigFile), rootConfigPath(pkg.Dir, prettierIgnoreFile))}))
 		}
 	}
+	errs = append(errs, plan.delegateFromRoot(pm, found, "prettier"))
+	return errors.Join(errs...)
 }
 
 func init() {
@@ -4,10 +4,8 @@ Copyright © 2024 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"fmt"
 	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
-	"os"
 	"slices"
 )
 
@@ -23,10 +21,10 @@ and usage of using your command. For example:
 Cobra is a CLI library for Go that empowers applications.
 This application is a tool to generate the needed files
 to quickly create a Cobra application.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		var tools []string
 
-		fmt.Println("Python project initializing....")
+		reportInfo("Python project initializing....")
 		form := huh.NewForm(
 			huh.NewGroup(
 				huh.NewMultiSelect[string]().Title("Setup Scripts").
@@ -41,19 +39,19 @@ to quickly create a Cobra application.`,
 		err := form.Run()
 
 		if err != nil {
-			fmt.Println("Uh oh:", err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Printf("Choose scripts: %s\n", tools)
+		reportInfo("Choose scripts: %s", tools)
 
 		if slices.Contains(tools, AUTO_TYPE) {
-			generateAutoTypeScript()
+			return generateAutoTypeScript()
 		}
+		return nil
 	},
 }
 
-func generateAutoTypeScript() {
+func generateAutoTypeScript() error {
 	const pythonFilename = `auto_type.py`
 	const pythonFileContent = `
 import argparse
@@ -109,13 +107,11 @@ if __name__ == "__main__":
   print(output)
 `
 
-	err := os.WriteFile(pythonFilename, []byte(pythonFileContent), 0644)
-	if err != nil {
-		fmt.Printf("Error creating %s: %v\n", pythonFilename, err)
-	} else {
-		fmt.Printf("%s created successfully\n", pythonFilename)
-		fmt.Println("You can read the document on https://github.com/JelleZijlstra/autotyping")
+	if err := writeFile(pythonFilename, []byte(pythonFileContent), 0644); err != nil {
+		return err
 	}
+	reportInfo("You can read the document on https://github.com/JelleZijlstra/autotyping")
+	return nil
 }
 
 func init() {
@@ -4,11 +4,15 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
+	"slices"
 
+	"github.com/CrossEvol/setup/common"
+	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
 )
 
@@ -19,144 +23,290 @@ var releaseItCmd = &cobra.Command{
 	Long: `This command sets up release-it for your project.
 
 It detects your package manager, installs release-it and the conventional changelog plugin,
-creates a configuration file (.release-it.json), and adds a 'release' script to package.json.
+asks how the changelog and release should look (language, emoji, visible commit types,
+version bump, GitHub/GitLab release, npm publish and before:init hooks), creates a
+configuration file (.release-it.json), and adds a 'release' script to package.json.
 release-it helps automate version bumping, changelog generation, and publishing.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		setupReleaseIt()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setupReleaseIt()
 	},
 }
 
-func setupReleaseIt() {
-	fmt.Println("Setting up release-it...")
-
-	// Define package managers and their commands
-	packageManagers := []struct {
-		name       string
-		installCmd []string
-	}{
-		{"pnpm", []string{"pnpm", "add", "-D", "release-it", "@release-it/conventional-changelog"}},
-		{"npm", []string{"npm", "install", "-D", "release-it", "@release-it/conventional-changelog"}},
-		{"yarn", []string{"yarn", "add", "-D", "release-it", "@release-it/conventional-changelog"}},
-		{"bun", []string{"bun", "add", "-D", "release-it", "@release-it/conventional-changelog"}},
-	}
-
-	var foundPackageManager string
-	var installCmdArgs []string
-
-	// Check for package managers in order
-	for _, pm := range packageManagers {
-		_, err := exec.LookPath(pm.name)
-		if err == nil {
-			foundPackageManager = pm.name
-			installCmdArgs = pm.installCmd
-			fmt.Printf("Found package manager: %s\n", foundPackageManager)
-			break // Use the first one found
+const (
+	changelogEnglish   = "en"
+	changelogChinese   = "zh"
+	changelogBilingual = "bilingual"
+
+	bumpPrompt       = "prompt"
+	bumpConventional = "conventional"
+
+	releaseGitHub = "github"
+	releaseGitLab = "gitlab"
+	releaseNone   = "none"
+)
+
+// releaseItOptions holds the answers of the release-it wizard.
+type releaseItOptions struct {
+	Types        []common.CommitType
+	Language     string
+	Emoji        bool
+	VisibleTypes []string
+	Bump         string
+	Release      string
+	NpmPublish   bool
+	Hooks        []string
+}
+
+// defaultReleaseItOptions reproduces the configuration written before the wizard existed.
+func defaultReleaseItOptions() releaseItOptions {
+	types := common.DefaultCommitTypes()
+	var visible []string
+	for _, t := range types {
+		if !t.Hidden {
+			visible = append(visible, t.Name)
 		}
 	}
+	return releaseItOptions{
+		Types:        types,
+		Language:     changelogBilingual,
+		Emoji:        true,
+		VisibleTypes: visible,
+		Bump:         bumpPrompt,
+		Release:      releaseGitHub,
+		NpmPublish:   !packageIsPrivate(),
+	}
+}
 
-	if foundPackageManager == "" {
-		fmt.Println("Error: No supported package manager (pnpm, npm, yarn, bun) found.")
-		fmt.Println("Please install one of these package managers and try again.")
-		return
+// packageIsPrivate reports whether package.json is marked "private": true.
+func packageIsPrivate() bool {
+	data, err := os.ReadFile("package.json")
+	if err != nil {
+		return false
 	}
+	var pkgJSON map[string]interface{}
+	if err := json.Unmarshal(data, &pkgJSON); err != nil {
+		return false
+	}
+	private, _ := pkgJSON["private"].(bool)
+	return private
+}
 
-	// 1. Install release-it packages
-	fmt.Printf("Running installation command: %s %v\n", installCmdArgs[0], installCmdArgs[1:])
-	installCmd := exec.Command(installCmdArgs[0], installCmdArgs[1:]...)
-	installCmd.Stdout = os.Stdout
-	installCmd.Stderr = os.Stderr
-	err := installCmd.Run()
+// packageScripts returns the names of the scripts declared in package.json.
+func packageScripts() map[string]bool {
+	scripts := make(map[string]bool)
+	data, err := os.ReadFile("package.json")
 	if err != nil {
-		fmt.Printf("Warning: Error installing release-it packages with %s: %v\n", foundPackageManager, err)
-		fmt.Println("Attempting to continue assuming packages are already installed.")
-		// Continue setup even if installation fails, maybe they are already installed
-	} else {
-		fmt.Println("release-it packages installed successfully.")
-	}
-
-	// 2. Create .release-it.json file
-	const releaseItConfigFile = `.release-it.json`
-	const releaseItConfigContent = `{
-  "plugins": {
-    "@release-it/conventional-changelog": {
-      "preset": {
-        "name": "conventionalcommits",
-        "types": [
-          { "type": "feat", "section": "✨ Features | 新功能" },
-          { "type": "fix", "section": "🐛 Bug Fixes | Bug 修复" },
-          { "type": "chore", "section": "🎫 Chores | 其他更新" },
-          { "type": "docs", "section": "📝 Documentation | 文档" },
-          { "type": "style", "section": "💄 Styles | 风格" },
-          { "type": "refactor", "section": "♻ Code Refactoring | 代码重构" },
-          { "type": "perf", "section": "⚡ Performance Improvements | 性能优化" },
-          { "type": "test", "section": "✅ Tests | 测试" },
-          { "type": "revert", "section": "⏪ Reverts | 回退" },
-          { "type": "build", "section": "👷‍ Build System | 构建" },
-          { "type": "ci", "section": "🔧 Continuous Integration | CI 配置" },
-          { "type": "config", "section": "🔨 CONFIG | 配置" }
-        ]
-      },
-      "infile": "CHANGELOG.md",
-      "ignoreRecommendedBump": true,
-      "strictSemVer": true
-    }
-  },
-  "git": {
-    "commitMessage": "chore: Release v${version}"
-  },
-  "github": {
-    "release": true,
-    "draft": false
-  }
+		return scripts
+	}
+	var pkgJSON map[string]interface{}
+	if err := json.Unmarshal(data, &pkgJSON); err != nil {
+		return scripts
+	}
+	entries, _ := pkgJSON["scripts"].(map[string]interface{})
+	for name := range entries {
+		scripts[name] = true
+	}
+	return scripts
+}
+
+// askReleaseItOptions runs the release-it wizard.
+func askReleaseItOptions(options *releaseItOptions, runCmdPrefix string) error {
+	var typeOptions []huh.Option[string]
+	for _, t := range options.Types {
+		typeOptions = append(typeOptions, huh.NewOption(fmt.Sprintf("%s (%s)", t.Name, t.Section.En), t.Name).Selected(!t.Hidden))
+	}
+
+	var hookOptions []huh.Option[string]
+	scripts := packageScripts()
+	for _, script := range []string{"lint", "test"} {
+		if scripts[script] {
+			command := fmt.Sprintf("%s %s", runCmdPrefix, script)
+			hookOptions = append(hookOptions, huh.NewOption(command, command).Selected(true))
+		}
+	}
+
+	groups := []*huh.Group{
+		huh.NewGroup(
+			huh.NewSelect[string]().Title("Changelog language").
+				Options(
+					huh.NewOption("English and Chinese", changelogBilingual),
+					huh.NewOption("English", changelogEnglish),
+					huh.NewOption("Chinese", changelogChinese),
+				).
+				Value(&options.Language),
+			huh.NewConfirm().Title("Prefix changelog sections with emoji?").
+				Value(&options.Emoji),
+		),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().Title("Changelog sections").
+				Options(typeOptions...).
+				Description("Unselected commit types are hidden from the changelog").
+				Value(&options.VisibleTypes),
+		),
+		huh.NewGroup(
+			huh.NewSelect[string]().Title("Version bump").
+				Options(
+					huh.NewOption("Ask for the increment on every release", bumpPrompt),
+					huh.NewOption("Recommend the increment from conventional commits", bumpConventional),
+				).
+				Value(&options.Bump),
+			huh.NewSelect[string]().Title("Hosted release").
+				Options(
+					huh.NewOption("GitHub", releaseGitHub),
+					huh.NewOption("GitLab", releaseGitLab),
+					huh.NewOption("None", releaseNone),
+				).
+				Value(&options.Release),
+			huh.NewConfirm().Title("Publish to npm?").
+				Value(&options.NpmPublish),
+		),
+	}
+	if len(hookOptions) > 0 {
+		groups = append(groups, huh.NewGroup(
+			huh.NewMultiSelect[string]().Title("before:init hooks").
+				Options(hookOptions...).
+				Description("Scripts that must pass before a release starts").
+				Value(&options.Hooks),
+		))
+	}
+
+	return huh.NewForm(groups...).Run()
+}
+
+// changelogSection renders the heading of a commit type for the chosen language.
+func changelogSection(section common.ChangelogSection, language string, emoji bool) string {
+	var title string
+	switch {
+	case language == changelogEnglish || section.Zh == "":
+		title = section.En
+	case language == changelogChinese || section.En == "":
+		title = section.Zh
+	default:
+		title = fmt.Sprintf("%s | %s", section.En, section.Zh)
+	}
+	if emoji && section.Emoji != "" {
+		title = section.Emoji + " " + title
+	}
+	return title
+}
+
+type releaseItPresetType struct {
+	Type    string `json:"type"`
+	Section string `json:"section"`
+	Hidden  bool   `json:"hidden,omitempty"`
+}
+
+type releaseItConfig struct {
+	Hooks   map[string][]string `json:"hooks,omitempty"`
+	Plugins map[string]any      `json:"plugins"`
+	Git     map[string]any      `json:"git"`
+	Npm     map[string]any      `json:"npm"`
+	GitHub  map[string]any      `json:"github,omitempty"`
+	GitLab  map[string]any      `json:"gitlab,omitempty"`
+}
+
+// renderReleaseItConfig builds .release-it.json from the wizard answers.
+func renderReleaseItConfig(options releaseItOptions) ([]byte, error) {
+	var types []releaseItPresetType
+	for _, t := range options.Types {
+		types = append(types, releaseItPresetType{
+			Type:    t.Name,
+			Section: changelogSection(t.Section, options.Language, options.Emoji),
+			Hidden:  !slices.Contains(options.VisibleTypes, t.Name),
+		})
+	}
+
+	config := releaseItConfig{
+		Plugins: map[string]any{
+			"@release-it/conventional-changelog": map[string]any{
+				"preset": map[string]any{
+					"name":  "conventionalcommits",
+					"types": types,
+				},
+				"infile":                "CHANGELOG.md",
+				"ignoreRecommendedBump": options.Bump == bumpPrompt,
+				"strictSemVer":          true,
+			},
+		},
+		Git: map[string]any{
+			"commitMessage": "chore: Release v${version}",
+		},
+		Npm: map[string]any{
+			"publish": options.NpmPublish,
+		},
+	}
+	if len(options.Hooks) > 0 {
+		config.Hooks = map[string][]string{"before:init": options.Hooks}
+	}
+	switch options.Release {
+	case releaseGitHub:
+		config.GitHub = map[string]any{"release": true, "draft": false}
+	case releaseGitLab:
+		config.GitLab = map[string]any{"release": true}
+	}
+
+	return marshalReleaseItConfig(config)
+}
+
+// marshalReleaseItConfig indents the config without escaping the changelog headings.
+func marshalReleaseItConfig(config any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(config); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// releaseItInstalls lists what setup releaseIt installs.
+func releaseItInstalls(workspacePlan) []installRequest {
+	return []installRequest{{What: "release-it packages", Packages: []string{"release-it", "@release-it/conventional-changelog"}}}
 }
-`
 
-	fmt.Printf("Creating %s...\n", releaseItConfigFile)
-	err = os.WriteFile(releaseItConfigFile, []byte(releaseItConfigContent), 0644)
+func setupReleaseIt() error {
+	currentTool = RELEASEIT
+	reportInfo("Setting up release-it...")
+
+	pm, found := detectPackageManager()
+	if !found {
+		return errNoPackageManager
+	}
+	runCmdPrefix := pm.RunCmd
+
+	// 1. Ask how the release should be configured
+	options := defaultReleaseItOptions()
+	err := askReleaseItOptions(&options, runCmdPrefix)
 	if err != nil {
-		fmt.Printf("Error creating %s: %v\n", releaseItConfigFile, err)
-		// Continue setup
-	} else {
-		fmt.Printf("%s created successfully.\n", releaseItConfigFile)
+		return err
 	}
 
-	// 3. Add "release": "release-it" script to package.json
-	packageJSONPath := "package.json"
-	packageJSONData, err := os.ReadFile(packageJSONPath)
+	// 2. Install release-it packages
+	var errs []error
+	err = runInstalls(pm, releaseItInstalls(workspacePlan{}))
 	if err != nil {
-		fmt.Printf("Error reading package.json: %v\n", err)
-		// Cannot update package.json, stop here for this part
-	} else {
-		var pkgJSON map[string]interface{}
-		err = json.Unmarshal(packageJSONData, &pkgJSON)
-		if err != nil {
-			fmt.Printf("Error parsing package.json: %v\n", err)
-			// Cannot update package.json, stop here for this part
-		} else {
-			scripts, ok := pkgJSON["scripts"].(map[string]interface{})
-			if !ok {
-				// Scripts key doesn't exist or isn't a map, create it
-				scripts = make(map[string]interface{})
-				pkgJSON["scripts"] = scripts
-			}
-			scripts["release"] = "release-it"
-
-			updatedData, err := json.MarshalIndent(pkgJSON, "", "  ")
-			if err != nil {
-				fmt.Printf("Error marshalling updated package.json: %v\n", err)
-				// Cannot update package.json, stop here for this part
-			} else {
-				err = os.WriteFile(packageJSONPath, updatedData, 0644)
-				if err != nil {
-					fmt.Printf("Error writing updated package.json: %v\n", err)
-				} else {
-					fmt.Println("'release' script added/updated in package.json.")
-				}
-			}
-		}
+		errs = append(errs, err)
+		reportWarning("Attempting to continue assuming packages are already installed.")
+		// Continue setup even if installation fails, maybe they are already installed
 	}
 
-	fmt.Println("release-it setup complete.")
+	// 3. Create .release-it.json file
+	releaseItConfigContent, err := renderReleaseItConfig(options)
+	if err != nil {
+		reportError("rendering %s: %v", releaseItConfigFile, err)
+		return errors.Join(append(errs, &configError{Path: releaseItConfigFile, Err: err})...)
+	}
+	errs = append(errs, writeFile(releaseItConfigFile, releaseItConfigContent, 0644))
+
+	// 4. Add "release": "release-it" script to package.json
+	errs = append(errs, setPackageScripts(".", map[string]string{"release": "release-it"}))
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	reportInfo("release-it setup complete.")
+	return nil
 }
 
 func init() {
@@ -0,0 +1,544 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/CrossEvol/setup/common"
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+// generatedFile is a file written by a setup command. Contents returns every
+// content the command may have written; nil means it cannot be known.
+type generatedFile struct {
+	Name     string
+	Contents func() []string
+}
+
+// toolRemoval describes what a setup command adds to a project.
+type toolRemoval struct {
+	Packages        []string
+	Marker          string // the package telling the tool is set up, Packages[0] when empty
+	Files           []generatedFile
+	Scripts         map[string][]string // script name -> the commands it may have written
+	HookLines       []string            // the hook lines it may have added
+	LintStagedLines []string            // the lint-staged entries it may have added
+}
+
+// marker returns the package telling the tool is set up in a project.
+func (r toolRemoval) marker() string {
+	if r.Marker != "" {
+		return r.Marker
+	}
+	return r.Packages[0]
+}
+
+// runLines returns the hook lines running a script, for every package manager.
+func runLines(script string) []string {
+	var lines []string
+	for _, pm := range common.PackageManagers {
+		lines = append(lines, pm.RunCmd+" "+script)
+	}
+	return lines
+}
+
+// stylelintConfigs renders stylelint.config.mjs for every combination of features.
+func stylelintConfigs() []string {
+	var configs []string
+	for _, scss := range []bool{false, true} {
+		for _, tailwind := range []bool{false, true} {
+			for _, styled := range []bool{false, true} {
+				configs = append(configs, styleFeatures{scss, tailwind, styled}.stylelintConfig())
+			}
+		}
+	}
+	return configs
+}
+
+// stylelintScripts returns the lint:style commands for every combination of features.
+func stylelintScripts() []string {
+	var scripts []string
+	for _, scss := range []bool{false, true} {
+		for _, styled := range []bool{false, true} {
+			scripts = append(scripts, fmt.Sprintf("stylelint \"%s\" --fix", styleFeatures{scss: scss, styled: styled}.styleGlob()))
+		}
+	}
+	return scripts
+}
+
+// stylelintLintStagedLines returns the lint-staged entries of setup stylelint,
+// including those registering the JS files of styled-components before only
+// the style sheets were.
+func stylelintLintStagedLines() []string {
+	var lines []string
+	for _, scss := range []bool{false, true} {
+		features := styleFeatures{scss: scss, styled: true}
+		for _, glob := range []string{features.styleSheetGlob(), features.styleGlob()} {
+			lines = append(lines, fmt.Sprintf("'%s': ['stylelint --fix'],", glob))
+		}
+	}
+	return lines
+}
+
+// lintStagedConfigs renders lint-staged.config.js for every package manager.
+func lintStagedConfigs() []string {
+	var configs []string
+	for _, pm := range common.PackageManagers {
+		configs = append(configs, fmt.Sprintf(lintStagedConfigTemplate, pm.RunCmd, pm.RunCmd))
+	}
+	return configs
+}
+
+// releaseItConfigs renders the default .release-it.json.
+func releaseItConfigs() []string {
+	content, err := renderReleaseItConfig(defaultReleaseItOptions())
+	if err != nil {
+		return nil
+	}
+	return []string{string(content)}
+}
+
+func constant(contents ...string) func() []string {
+	return func() []string { return contents }
+}
+
+// toolRemovals maps the setup commands to what they add.
+var toolRemovals = map[string]toolRemoval{
+	ESLINT: {
+		Packages: []string{"eslint", "globals", "@eslint/js", "typescript-eslint"},
+		Files:    []generatedFile{{eslintConfigFile, constant(eslintConfig)}},
+		Scripts:  map[string][]string{"lint": {"eslint . --fix"}},
+	},
+	PRETTIER: {
+		Packages: []string{"prettier"},
+		Files:    []generatedFile{{prettierConfigFile, constant(prettierConfig)}, {prettierIgnoreFile, prettierIgnoreContents}},
+		Scripts:  map[string][]string{"prettier": {"npx prettier . --write", "prettier . --write"}},
+	},
+	"linter": {
+		Packages: []string{"eslint", "globals", "@eslint/js", "typescript-eslint", "prettier", "eslint-config-prettier", "eslint-plugin-prettier"},
+		Marker:   "eslint-plugin-prettier",
+		Files:    []generatedFile{{eslintConfigFile, constant(linterEslintConfig)}, {prettierConfigFile, constant(prettierConfig)}, {prettierIgnoreFile, prettierIgnoreContents}},
+		Scripts:  map[string][]string{"lint": {"eslint . --fix"}},
+	},
+	STYLELINT: {
+		Packages:        []string{"stylelint", "stylelint-config-standard", "stylelint-config-standard-scss", "postcss-styled-syntax"},
+		Files:           []generatedFile{{stylelintConfigFile, stylelintConfigs}},
+		Scripts:         map[string][]string{"lint:style": stylelintScripts()},
+		LintStagedLines: stylelintLintStagedLines(),
+	},
+	VITEST: {
+		Packages: []string{"vitest"},
+		Files:    []generatedFile{{vitestConfigFile, constant(vitestConfig)}, {vitestSetupFile, constant(vitestSetupConfig)}},
+		Scripts:  map[string][]string{"test": {"vitest . ", "vitest --root ."}},
+	},
+	HUSKY: {
+		Packages: []string{"husky"},
+		Scripts:  map[string][]string{"prepare": {"husky"}},
+	},
+	COMMITLINT: {
+		Packages:  []string{"@commitlint/cli", "@commitlint/config-conventional"},
+		Files:     []generatedFile{{commitlintConfigFile, func() []string { return []string{renderCommitlintConfig(common.DefaultCommitTypes())} }}},
+		Scripts:   map[string][]string{"commitlint": {"commitlint --config commitlint.config.cjs -e -V"}},
+		HookLines: append(runLines("commitlint"), `setup commitlint check "$1"`),
+	},
+	LINTSTAGED: {
+		Packages:  []string{"lint-staged"},
+		Files:     []generatedFile{{lintStagedConfigFile, lintStagedConfigs}},
+		Scripts:   map[string][]string{"pre-commit": {"lint-staged"}},
+		HookLines: runLines("pre-commit"),
+	},
+	RELEASEIT: {
+		Packages: []string{"release-it", "@release-it/conventional-changelog"},
+		Files:    []generatedFile{{releaseItConfigFile, releaseItConfigs}},
+		Scripts:  map[string][]string{"release": {"release-it"}},
+	},
+	CHANGESETS: {
+		Packages: []string{"@changesets/cli"},
+		Files: []generatedFile{
+			{filepath.Join(changesetDir, "config.json"), nil},
+			{filepath.Join(changesetDir, "README.md"), constant(changesetReadme)},
+			{filepath.Join(".github", "workflows", "release.yml"), nil},
+		},
+		// 'release' ran changeset publish before 'changeset:publish' did
+		Scripts: map[string][]string{"changeset": {"changeset"}, "version-packages": {"changeset version"},
+			"changeset:publish": {"changeset publish"}, "release": {"changeset publish"}},
+	},
+}
+
+// removeCmd represents the remove command
+var removeCmd = &cobra.Command{
+	Use:   "remove <tool>",
+	Short: "Undo what a setup command added to the project",
+	Long: `Remove a tool set up by this CLI: the reverse of 'setup <tool>'.
+
+It uninstalls the packages the setup command added with the detected package
+manager, deletes the config files it generated, removes the package.json scripts
+it added and strips its lines from the Git hooks. Removing husky also undoes the
+'prepare' script and deletes the .husky directory.
+
+Config files are only deleted when they are unchanged; edited ones are kept
+unless you confirm (or pass --yes). Scripts and hook lines are only removed as
+they were written, and packages another set up tool still uses are kept. Inside a workspace the packages are cleaned
+up as well.`,
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return removableTools(), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		removal, ok := toolRemovals[args[0]]
+		if !ok {
+			return fmt.Errorf("unknown tool %q. Supported tools: %s", args[0], strings.Join(removableTools(), ", "))
+		}
+		return removeTool(args[0], removal, yes)
+	},
+}
+
+func removableTools() []string {
+	tools := make([]string, 0, len(toolRemovals))
+	for tool := range toolRemovals {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	return tools
+}
+
+func removeTool(tool string, removal toolRemoval, yes bool) error {
+	currentTool = tool
+	reportInfo("Removing %s...", tool)
+
+	pm, found := projectPackageManager()
+	if !found {
+		reportWarning("No supported package manager (pnpm, npm, yarn, bun) found. Packages will not be uninstalled.")
+	}
+
+	// The root first, then every workspace package
+	dirs := []string{"."}
+	var packages []common.WorkspacePackage
+	if common.IsWorkspaceRoot() {
+		packages = common.WorkspacePackages()
+	}
+	for i := range packages {
+		dirs = append(dirs, packages[i].Dir)
+	}
+
+	var errs []error
+	for i, dir := range dirs {
+		var pkg *common.WorkspacePackage
+		if i > 0 {
+			pkg = &packages[i-1]
+		}
+
+		if found {
+			if declared := removablePackages(tool, dir, removal.Packages); len(declared) > 0 {
+				what := fmt.Sprintf("Uninstalling %s with %s", strings.Join(declared, ", "), pm.Name)
+				if err := runCommand(pm.RemoveArgs(declared, pkg), what); err != nil {
+					errs = append(errs, &installError{What: "uninstalling " + strings.Join(declared, ", "), Err: err})
+				}
+			}
+		}
+
+		for _, file := range removal.Files {
+			errs = append(errs, removeGeneratedFile(filepath.Join(dir, file.Name), file.Contents, yes))
+		}
+
+		var delegated map[string]string
+		if i == 0 && found {
+			delegated = make(map[string]string)
+			for name := range removal.Scripts {
+				delegated[name] = workspaceRunScript(pm, name)
+			}
+		}
+		errs = append(errs, removePackageScripts(dir, removal.Scripts, delegated))
+	}
+
+	for _, hook := range gitHooks() {
+		errs = append(errs, stripLines(hook, removal.HookLines, true))
+	}
+	errs = append(errs, stripLines(lintStagedConfigFile, removal.LintStagedLines, false))
+
+	if tool == HUSKY {
+		errs = append(errs, removeHusky(yes))
+	}
+	if tool == CHANGESETS {
+		// Pending changesets are kept, the directory goes away once empty
+		_ = os.Remove(changesetDir)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	reportInfo("%s removed.", tool)
+	return nil
+}
+
+// removablePackages returns the packages of a tool listed in the package.json
+// inside dir, but those another tool set up there still lists: removing eslint
+// keeps the packages linter needs.
+func removablePackages(tool, dir string, packages []string) []string {
+	declared := declaredPackages(dir, packages)
+	var kept []string
+	for other, removal := range toolRemovals {
+		if other == tool || slices.Contains(packages, removal.marker()) || len(declaredPackages(dir, []string{removal.marker()})) == 0 {
+			continue
+		}
+		declared = slices.DeleteFunc(declared, func(name string) bool {
+			if slices.Contains(removal.Packages, name) {
+				kept = append(kept, fmt.Sprintf("%s (%s)", name, other))
+				return true
+			}
+			return false
+		})
+	}
+	if len(kept) > 0 {
+		sort.Strings(kept)
+		reportInfo("Keeping %s, still used by another tool.", strings.Join(kept, ", "))
+	}
+	return declared
+}
+
+// declaredPackages returns the packages listed in the package.json inside dir.
+func declaredPackages(dir string, packages []string) []string {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil
+	}
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil
+	}
+	var declared []string
+	for _, name := range packages {
+		if pkg.hasDependency(name) {
+			declared = append(declared, name)
+		}
+	}
+	return declared
+}
+
+// confirmRemoval asks before deleting something the user may have edited.
+func confirmRemoval(title string) bool {
+	remove := false
+	err := huh.NewConfirm().
+		Title(title).
+		Affirmative("Delete").
+		Negative("Keep").
+		Value(&remove).
+		Run()
+	if err != nil {
+		return false
+	}
+	return remove
+}
+
+// removeGeneratedFile deletes a generated file when it is unchanged, or when
+// the user confirms the deletion of an edited one.
+func removeGeneratedFile(path string, contents func() []string, yes bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	unchanged := contents != nil && slices.Contains(contents(), string(data))
+	if !unchanged && !yes && !confirmRemoval(fmt.Sprintf("%s was changed since it was generated. Delete it anyway?", path)) {
+		reportFileSkipped(path, "changed since it was generated, kept")
+		return nil
+	}
+
+	if err := os.Remove(path); err != nil {
+		reportError("deleting %s: %v", path, err)
+		return &configError{Path: path, Err: err}
+	}
+	reportFileDeleted(path)
+	return nil
+}
+
+// generatedScript reports whether a script command is one of the commands a
+// setup command writes, or its variant for a workspace package pointing at the
+// root config with --config.
+func generatedScript(command string, commands []string) bool {
+	for _, generated := range commands {
+		if command == generated || strings.HasPrefix(command, generated+" --config ") {
+			return true
+		}
+	}
+	return false
+}
+
+// removePackageScripts deletes the scripts still running the commands written
+// by the setup command, or that delegate to the workspace packages.
+func removePackageScripts(dir string, scripts map[string][]string, delegated map[string]string) error {
+	packageJSONPath := filepath.Join(dir, "package.json")
+	packageJSONData, err := os.ReadFile(packageJSONPath)
+	if err != nil {
+		return nil
+	}
+
+	var pkgJSON map[string]interface{}
+	err = json.Unmarshal(packageJSONData, &pkgJSON)
+	if err != nil {
+		reportError("parsing %s: %v", packageJSONPath, err)
+		return &configError{Path: packageJSONPath, Err: err}
+	}
+	existing, ok := pkgJSON["scripts"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var removed []string
+	for name, commands := range scripts {
+		command, ok := existing[name].(string)
+		if !ok {
+			continue
+		}
+		switch {
+		case command == delegated[name]:
+			delete(existing, name)
+		case name == "prepare":
+			// Husky may share 'prepare' with other commands
+			parts := strings.Split(command, "&&")
+			var kept []string
+			for _, part := range parts {
+				if !generatedScript(strings.TrimSpace(part), commands) {
+					kept = append(kept, strings.TrimSpace(part))
+				}
+			}
+			if len(kept) == len(parts) {
+				continue
+			}
+			if len(kept) == 0 {
+				delete(existing, name)
+			} else {
+				existing[name] = strings.Join(kept, " && ")
+			}
+		case generatedScript(command, commands):
+			delete(existing, name)
+		default:
+			continue
+		}
+		removed = append(removed, name)
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	sort.Strings(removed)
+
+	updatedData, err := json.MarshalIndent(pkgJSON, "", "  ")
+	if err != nil {
+		reportError("marshalling updated %s: %v", packageJSONPath, err)
+		return &configError{Path: packageJSONPath, Err: err}
+	}
+	err = os.WriteFile(packageJSONPath, updatedData, 0644)
+	if err != nil {
+		reportError("writing updated %s: %v", packageJSONPath, err)
+		return &configError{Path: packageJSONPath, Err: err}
+	}
+	for _, name := range removed {
+		reportScriptRemoved(packageJSONPath, name)
+	}
+	return nil
+}
+
+// stripLines removes the given lines from a file, whatever their indentation.
+// Hooks left with nothing but the shebang and comments are deleted.
+func stripLines(path string, lines []string, hook bool) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var kept []string
+	removed, commands := 0, 0
+	for _, line := range strings.Split(string(data), "\n") {
+		if slices.Contains(lines, strings.TrimSpace(line)) {
+			removed++
+			continue
+		}
+		if trimmed := strings.TrimSpace(line); trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			commands++
+		}
+		kept = append(kept, line)
+	}
+	if removed == 0 {
+		return nil
+	}
+
+	if hook && commands == 0 {
+		if err := os.Remove(path); err != nil {
+			reportError("deleting %s: %v", path, err)
+			return &configError{Path: path, Err: err}
+		}
+		reportFileDeleted(path)
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return &configError{Path: path, Err: err}
+	}
+	err = os.WriteFile(path, []byte(strings.Join(kept, "\n")), info.Mode().Perm())
+	if err != nil {
+		reportError("writing %s: %v", path, err)
+		return &configError{Path: path, Err: err}
+	}
+	message := fmt.Sprintf("%d line(s) removed from %s.", removed, path)
+	if hook {
+		reportHookUpdated(path, "", message)
+	} else {
+		emit(event{Type: eventFileWritten, Path: path, Message: message})
+	}
+	return nil
+}
+
+// removeHusky deletes the .husky directory and resets core.hooksPath, which
+// 'husky' points at .husky/_.
+func removeHusky(yes bool) error {
+	const huskyDir = ".husky"
+
+	var errs []error
+	if out, err := exec.Command("git", "config", "--get", "core.hooksPath").Output(); err == nil &&
+		strings.HasPrefix(strings.TrimSpace(string(out)), huskyDir) {
+		if err := exec.Command("git", "config", "--unset", "core.hooksPath").Run(); err != nil {
+			reportError("resetting core.hooksPath: %v", err)
+			errs = append(errs, &configError{Path: "core.hooksPath", Err: err})
+		} else {
+			reportInfo("core.hooksPath reset.")
+		}
+	}
+
+	hooks := huskyHooks()
+	if _, err := os.Stat(huskyDir); err != nil {
+		return errors.Join(errs...)
+	}
+	if len(hooks) > 0 && !yes && !confirmRemoval(fmt.Sprintf("%s still contains %s. Delete it anyway?", huskyDir, strings.Join(hooks, ", "))) {
+		reportFileSkipped(huskyDir, "still contains hooks, kept")
+		return errors.Join(errs...)
+	}
+	if err := os.RemoveAll(huskyDir); err != nil {
+		reportError("deleting %s: %v", huskyDir, err)
+		return errors.Join(append(errs, &configError{Path: huskyDir, Err: err})...)
+	}
+	reportFileDeleted(huskyDir)
+	return errors.Join(errs...)
+}
+
+func init() {
+	rootCmd.AddCommand(removeCmd)
+
+	removeCmd.Flags().BoolP("yes", "y", false, "Delete edited config files and hooks without asking")
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestRemovablePackages(t *testing.T) {
+	t.Chdir(t.TempDir())
+	captureEvents(t)
+	os.WriteFile("package.json", []byte(`{"devDependencies": {
+  "eslint": "^9.9.0", "globals": "^15.9.0", "@eslint/js": "^9.9.0",
+  "prettier": "^3.3.3", "eslint-config-prettier": "^9.1.0", "eslint-plugin-prettier": "^5.2.1",
+  "vitest": "^2.0.5"
+}}`), 0644)
+
+	tests := []struct {
+		tool string
+		want []string
+	}{
+		// linter still uses them
+		{ESLINT, nil},
+		{PRETTIER, nil},
+		{"linter", []string{"eslint", "globals", "@eslint/js", "prettier", "eslint-config-prettier", "eslint-plugin-prettier"}},
+		{VITEST, []string{"vitest"}},
+	}
+	for _, tt := range tests {
+		if got := removablePackages(tt.tool, ".", toolRemovals[tt.tool].Packages); !slices.Equal(got, tt.want) {
+			t.Errorf("removablePackages(%s) = %v, want %v", tt.tool, got, tt.want)
+		}
+	}
+}
+
+func TestRemovePackageScripts(t *testing.T) {
+	t.Chdir(t.TempDir())
+	captureEvents(t)
+	os.WriteFile("package.json", []byte(`{"scripts": {
+  "lint": "eslint . --fix --config ../../eslint.config.mjs",
+  "lint:types": "eslint . --fix && tsc --noEmit",
+  "prepare": "husky && patch-package",
+  "postinstall": "husky-ci setup",
+  "commitlint": "commitlint --config commitlint.config.cjs -e -V",
+  "release": "release-it",
+  "changeset:publish": "changeset publish"
+}}`), 0644)
+
+	scripts := map[string][]string{"lint:types": {"eslint . --fix"}, "postinstall": {"husky"}}
+	for _, tool := range []string{ESLINT, HUSKY, COMMITLINT, CHANGESETS} {
+		for name, commands := range toolRemovals[tool].Scripts {
+			scripts[name] = append(scripts[name], commands...)
+		}
+	}
+	if err := removePackageScripts(".", scripts, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile("package.json")
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		// Changed since it was generated, or only sharing the name
+		"lint:types":  "eslint . --fix && tsc --noEmit",
+		"postinstall": "husky-ci setup",
+		"prepare":     "patch-package",
+		"release":     "release-it",
+	}
+	if !reflect.DeepEqual(pkg.Scripts, want) {
+		t.Errorf("scripts = %v, want %v", pkg.Scripts, want)
+	}
+}
+
+func TestStripLines(t *testing.T) {
+	t.Chdir(t.TempDir())
+	captureEvents(t)
+	os.Mkdir(".husky", 0755)
+	os.WriteFile(".husky/commit-msg", []byte("#!/usr/bin/env sh\npnpm run commitlint\nnpx --no -- commitlint --edit \"$1\"\n"), 0755)
+	os.WriteFile(".husky/pre-commit", []byte("#!/usr/bin/env sh\n  npm run pre-commit\n"), 0755)
+
+	if err := stripLines(".husky/commit-msg", toolRemovals[COMMITLINT].HookLines, true); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(".husky/commit-msg")
+	if want := "#!/usr/bin/env sh\nnpx --no -- commitlint --edit \"$1\"\n"; string(data) != want {
+		t.Errorf("commit-msg = %q, want %q", data, want)
+	}
+
+	// Nothing left to run, the hook goes away
+	if err := stripLines(".husky/pre-commit", toolRemovals[LINTSTAGED].HookLines, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(".husky/pre-commit"); !os.IsNotExist(err) {
+		t.Errorf("pre-commit was kept: %v", err)
+	}
+
+	os.WriteFile(lintStagedConfigFile, []byte("export default {\n  '**/*.{css,scss}': ['stylelint --fix'],\n  '**/*.css': ['stylelint --fix', 'prettier --write'],\n}\n"), 0644)
+	if err := stripLines(lintStagedConfigFile, toolRemovals[STYLELINT].LintStagedLines, false); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(lintStagedConfigFile)
+	if want := "export default {\n  '**/*.css': ['stylelint --fix', 'prettier --write'],\n}\n"; string(data) != want {
+		t.Errorf("%s = %q, want %q", lintStagedConfigFile, data, want)
+	}
+}
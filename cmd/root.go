@@ -4,6 +4,7 @@ Copyright © 2024 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -23,14 +24,37 @@ Run 'setup --help' to see available commands and options.`,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if outputFormat != outputText && outputFormat != outputJSON {
+			return fmt.Errorf("invalid --output %q: use text or json", outputFormat)
+		}
+		switch workspaceMode {
+		case "", workspaceRoot, workspacePackages, workspaceBoth:
+		default:
+			return fmt.Errorf("invalid --workspace-mode %q: use root, packages or both", workspaceMode)
+		}
+		// Yarn 2+ has no --offline flag, it reads the setting from the environment
+		if offlineMode {
+			if err := os.Setenv("YARN_ENABLE_NETWORK", "0"); err != nil {
+				return err
+			}
+		}
+		// The arguments are fine, failures from here on are not usage errors
+		cmd.SilenceUsage = true
+		return nil
+	},
+	// Errors are reported as events, see Execute
+	SilenceErrors: true,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// The exit status tells the kind of failure apart, see exitCode.
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		reportError("%v", err)
+		os.Exit(exitCode(err))
 	}
 }
 
@@ -44,6 +68,10 @@ func init() {
 	// will be global for your application.
 
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.setup.yaml)")
+	rootCmd.PersistentFlags().StringVar(&workspaceMode, "workspace-mode", "", "In a monorepo, set tools up at the 'root', in the 'packages' or 'both' instead of asking")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputText, "Report progress as 'text' or as newline-delimited 'json' events")
+	rootCmd.PersistentFlags().BoolVar(&latestVersions, "latest", false, "Install the latest versions instead of the tested ranges of the version manifest")
+	rootCmd.PersistentFlags().BoolVar(&offlineMode, "offline", false, "Never use the network: install from the package manager's store or cache only")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
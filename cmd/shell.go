@@ -4,6 +4,7 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"github.com/spf13/cobra"
 	"os"
@@ -16,14 +17,14 @@ var shellCmd = &cobra.Command{
 	Use:   "shell",
 	Short: "Add shell header",
 	Long:  `for tab completion in terminal, provide the shell header is necessary, should provide "#!/bin/bash" for suffix of .sh , "#!/usr/bin/env pwsh" for suffix of .ps1`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		var nothingTodo = true
+		var errs []error
 
 		// Get current directory
 		currentDir, err := os.Getwd()
 		if err != nil {
-			fmt.Printf("Error getting current directory: %v\n", err)
-			return
+			return fmt.Errorf("getting current directory: %w", err)
 		}
 
 		// Walk through directory
@@ -51,7 +52,8 @@ var shellCmd = &cobra.Command{
 			// Read file content
 			content, err := os.ReadFile(path)
 			if err != nil {
-				fmt.Printf("Error reading file %s: %v\n", path, err)
+				reportError("reading file %s: %v", path, err)
+				errs = append(errs, &configError{Path: path, Err: err})
 				return nil
 			}
 
@@ -68,10 +70,11 @@ var shellCmd = &cobra.Command{
 				// Write back to file
 				err = os.WriteFile(path, []byte(newContent), info.Mode())
 				if err != nil {
-					fmt.Printf("Error writing to file %s: %v\n", path, err)
+					reportError("writing to file %s: %v", path, err)
+					errs = append(errs, &configError{Path: path, Err: err})
 					return nil
 				}
-				fmt.Printf("Added shell header to %s\n", path)
+				emit(event{Type: eventFileWritten, Path: path, Value: shebang, Message: fmt.Sprintf("Added shell header to %s", path)})
 				nothingTodo = false
 			}
 
@@ -79,13 +82,13 @@ var shellCmd = &cobra.Command{
 		})
 
 		if err != nil {
-			fmt.Printf("Error walking directory: %v\n", err)
-			return
+			return fmt.Errorf("walking directory: %w", err)
 		}
 
-		if nothingTodo {
-			fmt.Printf("Nothing to do!\n")
+		if nothingTodo && len(errs) == 0 {
+			reportInfo("Nothing to do!")
 		}
+		return errors.Join(errs...)
 	},
 }
 
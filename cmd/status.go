@@ -0,0 +1,252 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/CrossEvol/setup/common"
+	"github.com/spf13/cobra"
+)
+
+// statusTool describes how to find one of the supported tools in a project.
+type statusTool struct {
+	Name        string
+	Package     string
+	Python      bool
+	ConfigFiles []string
+	Keyword     string
+}
+
+// statusTools lists the tools reported by 'setup status'.
+var statusTools = []statusTool{
+	{ESLINT, "eslint", false, []string{"eslint.config.js", "eslint.config.mjs", "eslint.config.cjs", "eslint.config.ts", ".eslintrc", ".eslintrc.js", ".eslintrc.cjs", ".eslintrc.json", ".eslintrc.yml", ".eslintrc.yaml"}, "eslint"},
+	{PRETTIER, "prettier", false, []string{".prettierrc", ".prettierrc.json", ".prettierrc.js", ".prettierrc.cjs", ".prettierrc.mjs", ".prettierrc.yml", ".prettierrc.yaml", "prettier.config.js", "prettier.config.mjs", "prettier.config.cjs"}, "prettier"},
+	{STYLELINT, "stylelint", false, []string{"stylelint.config.mjs", "stylelint.config.js", "stylelint.config.cjs", ".stylelintrc", ".stylelintrc.json"}, "stylelint"},
+	{VITEST, "vitest", false, []string{"vitest.config.ts", "vitest.config.mts", "vitest.config.js", "vitest.config.mjs"}, "vitest"},
+	{HUSKY, "husky", false, []string{".husky"}, "husky"},
+	{COMMITLINT, "@commitlint/cli", false, []string{commitlintConfigFile, "commitlint.config.js", "commitlint.config.mjs", "commitlint.config.ts", ".commitlintrc", ".commitlintrc.json"}, "commitlint"},
+	{LINTSTAGED, "lint-staged", false, []string{"lint-staged.config.js", "lint-staged.config.mjs", "lint-staged.config.cjs", ".lintstagedrc", ".lintstagedrc.json"}, "lint-staged"},
+	{RELEASEIT, "release-it", false, []string{releaseItConfigFile, ".release-it.js", ".release-it.cjs", ".release-it.yaml", ".release-it.yml", ".release-it.toml"}, "release-it"},
+	{CHANGESETS, "@changesets/cli", false, []string{".changeset/config.json"}, "changeset"},
+	{AUTO_TYPE, "autotyping", true, []string{"auto_type.py"}, "auto_type"},
+	{"mypy", "mypy", true, []string{"mypy.ini", ".mypy.ini", "pyproject.toml", "setup.cfg"}, "mypy"},
+	{"black", "black", true, []string{"pyproject.toml"}, "black"},
+}
+
+// configSections are the sections of the shared Python config files holding
+// the config of a tool; the file only counts as its config with the section.
+var configSections = map[string]map[string]string{
+	"mypy":  {"pyproject.toml": "[tool.mypy]", "setup.cfg": "[mypy]"},
+	"black": {"pyproject.toml": "[tool.black]"},
+}
+
+// hasConfig reports whether a file holds the config of a tool.
+func hasConfig(tool statusTool, file string) bool {
+	section, shared := configSections[tool.Package][file]
+	if !shared {
+		_, err := os.Stat(file)
+		return err == nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == section {
+			return true
+		}
+	}
+	return false
+}
+
+// hookLine is a line of a Git hook related to a tool.
+type hookLine struct {
+	File string `json:"file"`
+	Line string `json:"line"`
+}
+
+// toolStatus is the inventory entry of a tool.
+type toolStatus struct {
+	Name     string            `json:"name"`
+	Package  string            `json:"package"`
+	Present  bool              `json:"present"`
+	Config   string            `json:"config,omitempty"`
+	Declared string            `json:"declared,omitempty"`
+	Locked   string            `json:"locked,omitempty"`
+	Lockfile string            `json:"lockfile,omitempty"`
+	Scripts  map[string]string `json:"scripts,omitempty"`
+	Hooks    []hookLine        `json:"hooks,omitempty"`
+}
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List the tools set up in the project",
+	Long: `List which of the supported tools are present in the current project.
+
+For each tool it shows the config file, the version declared in package.json
+(or requirements.txt/pyproject.toml for Python tooling), the version resolved in
+the lockfile, the related package.json scripts and the Git hook lines that run
+it. Nothing is modified.
+
+Use --json to collect the inventory from scripts.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		inventory := collectStatus()
+		if asJSON {
+			data, err := json.MarshalIndent(inventory, "", "  ")
+			if err != nil {
+				return fmt.Errorf("encoding status: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+		printStatus(inventory)
+		return nil
+	},
+}
+
+// gitHooks returns the hook files of the project: Husky's hooks, or the plain
+// Git hooks when Husky is not used.
+func gitHooks() []string {
+	if hooks := huskyHooks(); len(hooks) > 0 {
+		return hooks
+	}
+	entries, err := os.ReadDir(".git/hooks")
+	if err != nil {
+		return nil
+	}
+	var hooks []string
+	for _, entry := range entries {
+		if !entry.IsDir() && !strings.HasSuffix(entry.Name(), ".sample") {
+			hooks = append(hooks, ".git/hooks/"+entry.Name())
+		}
+	}
+	return hooks
+}
+
+func collectStatus() []toolStatus {
+	pkg, _ := readPackageJSON()
+	hooks := gitHooks()
+
+	var inventory []toolStatus
+	for _, tool := range statusTools {
+		status := toolStatus{Name: tool.Name, Package: tool.Package}
+
+		for _, file := range tool.ConfigFiles {
+			if hasConfig(tool, file) {
+				status.Config = file
+				break
+			}
+		}
+
+		if tool.Python {
+			status.Declared = common.PythonDeclaredVersion(tool.Package)
+			status.Locked, status.Lockfile = common.PythonLockedVersion(tool.Package)
+		} else {
+			if version, ok := pkg.DevDependencies[tool.Package]; ok {
+				status.Declared = version
+			} else if version, ok := pkg.Dependencies[tool.Package]; ok {
+				status.Declared = version
+			}
+			status.Locked, status.Lockfile = common.LockedVersion(tool.Package)
+		}
+
+		for name, script := range pkg.Scripts {
+			if strings.Contains(script, tool.Keyword) {
+				if status.Scripts == nil {
+					status.Scripts = make(map[string]string)
+				}
+				status.Scripts[name] = script
+			}
+		}
+
+		for _, hook := range hooks {
+			status.Hooks = append(status.Hooks, matchHookLines(hook, tool.Keyword, status.Scripts)...)
+		}
+
+		status.Present = status.Config != "" || status.Declared != "" || status.Locked != ""
+		inventory = append(inventory, status)
+	}
+	return inventory
+}
+
+// matchHookLines returns the lines of a hook that run the tool, either directly
+// or through one of its scripts.
+func matchHookLines(hook, keyword string, scripts map[string]string) []hookLine {
+	file, err := os.Open(hook)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var lines []hookLine
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		matched := strings.Contains(line, keyword)
+		for _, reference := range scriptReferences(line) {
+			if _, ok := scripts[reference.Name]; ok {
+				matched = true
+			}
+		}
+		if matched {
+			lines = append(lines, hookLine{File: hook, Line: line})
+		}
+	}
+	return lines
+}
+
+func printStatus(inventory []toolStatus) {
+	for _, status := range inventory {
+		if !status.Present {
+			fmt.Printf("✗ %s: not set up\n", status.Name)
+			continue
+		}
+
+		fmt.Printf("✔ %s\n", status.Name)
+		fmt.Printf("    config:   %s\n", orDash(status.Config))
+		fmt.Printf("    declared: %s\n", orDash(status.Declared))
+		if status.Locked != "" {
+			fmt.Printf("    locked:   %s (%s)\n", status.Locked, status.Lockfile)
+		} else {
+			fmt.Printf("    locked:   -\n")
+		}
+
+		names := make([]string, 0, len(status.Scripts))
+		for name := range status.Scripts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("    script:   %s = %s\n", name, status.Scripts[name])
+		}
+		for _, hook := range status.Hooks {
+			fmt.Printf("    hook:     %s: %s\n", hook.File, hook.Line)
+		}
+	}
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().Bool("json", false, "Print the inventory as JSON")
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+)
+
+func TestStatusToolsConfigFilesAreDistinct(t *testing.T) {
+	for _, tool := range statusTools {
+		seen := make(map[string]bool)
+		for _, file := range tool.ConfigFiles {
+			if seen[file] {
+				t.Errorf("%s lists %s twice", tool.Name, file)
+			}
+			seen[file] = true
+		}
+	}
+}
+
+func TestCollectStatusPythonTools(t *testing.T) {
+	t.Chdir(t.TempDir())
+	files := map[string]string{
+		"pyproject.toml":       "[project]\nname = \"demo\"\n\n[tool.black]\nline-length = 100\n",
+		"requirements-dev.txt": "black>=24.8.0\nmypy==1.11.2\n",
+		"uv.lock":              "[[package]]\nname = \"black\"\nversion = \"24.8.0\"\n\n[[package]]\nname = \"mypy\"\nversion = \"1.11.2\"\n",
+		"commitlint.config.js": "export default {}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := map[string]toolStatus{
+		// pyproject.toml has no [tool.mypy], mypy is only declared
+		"mypy":     {Present: true, Declared: "==1.11.2", Locked: "1.11.2", Lockfile: "uv.lock"},
+		"black":    {Present: true, Config: "pyproject.toml", Declared: ">=24.8.0", Locked: "24.8.0", Lockfile: "uv.lock"},
+		AUTO_TYPE:  {},
+		COMMITLINT: {Present: true, Config: "commitlint.config.js"},
+	}
+	for _, status := range collectStatus() {
+		w, ok := want[status.Name]
+		if !ok {
+			continue
+		}
+		if status.Present != w.Present || status.Config != w.Config || status.Declared != w.Declared ||
+			status.Locked != w.Locked || status.Lockfile != w.Lockfile {
+			t.Errorf("%s = %+v, want %+v", status.Name, status, w)
+		}
+		delete(want, status.Name)
+	}
+	for name := range want {
+		t.Errorf("%s is missing from the status", name)
+	}
+}
@@ -0,0 +1,241 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// stylelintCmd represents the stylelint command
+var stylelintCmd = &cobra.Command{
+	Use:   "stylelint",
+	Short: "Set up Stylelint for CSS, SCSS and CSS-in-JS",
+	Long: `This command sets up Stylelint for your project.
+
+It inspects package.json to detect plain CSS, SCSS (sass), Tailwind CSS and
+styled-components usage, installs the matching shared config, creates
+stylelint.config.mjs, adds a 'lint:style' script to package.json and registers
+the style globs with lint-staged when lint-staged.config.js exists.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setupStylelint()
+	},
+}
+
+// styleFeatures describes the styling approaches found in a project.
+type styleFeatures struct {
+	scss     bool
+	tailwind bool
+	styled   bool
+}
+
+// detectStyleFeatures reads the dependencies declared in package.json.
+func detectStyleFeatures() styleFeatures {
+	var features styleFeatures
+
+	packageJSONData, err := os.ReadFile("package.json")
+	if err != nil {
+		return features
+	}
+
+	var pkgJSON map[string]interface{}
+	if err := json.Unmarshal(packageJSONData, &pkgJSON); err != nil {
+		return features
+	}
+
+	for _, field := range []string{"dependencies", "devDependencies"} {
+		deps, ok := pkgJSON[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for name := range deps {
+			switch name {
+			case "sass", "sass-embedded", "node-sass":
+				features.scss = true
+			case "tailwindcss":
+				features.tailwind = true
+			case "styled-components", "@emotion/styled":
+				features.styled = true
+			}
+		}
+	}
+
+	return features
+}
+
+// stylelintPackages returns the packages needed for the detected features.
+func (f styleFeatures) stylelintPackages() []string {
+	packages := []string{"stylelint"}
+	if f.scss {
+		packages = append(packages, "stylelint-config-standard-scss")
+	} else {
+		packages = append(packages, "stylelint-config-standard")
+	}
+	if f.styled {
+		packages = append(packages, "postcss-styled-syntax")
+	}
+	return packages
+}
+
+// styleGlob returns the glob of files Stylelint should check.
+func (f styleFeatures) styleGlob() string {
+	return f.glob(f.styled)
+}
+
+// styleSheetGlob returns the glob of the style sheets alone. lint-staged runs
+// the tasks of overlapping globs at the same time, so the JS files of
+// styled-components are left to the JS linters there.
+func (f styleFeatures) styleSheetGlob() string {
+	return f.glob(false)
+}
+
+func (f styleFeatures) glob(withScripts bool) string {
+	exts := []string{"css"}
+	if f.scss {
+		exts = append(exts, "scss")
+	}
+	if withScripts {
+		exts = append(exts, "js", "jsx", "ts", "tsx")
+	}
+	if len(exts) == 1 {
+		return "**/*." + exts[0]
+	}
+	return "**/*.{" + strings.Join(exts, ",") + "}"
+}
+
+// stylelintConfig renders stylelint.config.mjs for the detected features.
+func (f styleFeatures) stylelintConfig() string {
+	var b strings.Builder
+
+	baseConfig := "stylelint-config-standard"
+	unknownAtRule := "at-rule-no-unknown"
+	if f.scss {
+		baseConfig = "stylelint-config-standard-scss"
+		unknownAtRule = "scss/at-rule-no-unknown"
+	}
+
+	b.WriteString("/** @type {import('stylelint').Config} */\n")
+	b.WriteString("export default {\n")
+	fmt.Fprintf(&b, "  extends: ['%s'],\n", baseConfig)
+	b.WriteString("  rules: {\n")
+	if f.tailwind {
+		fmt.Fprintf(&b, "    '%s': [\n", unknownAtRule)
+		b.WriteString("      true,\n")
+		b.WriteString("      {\n")
+		b.WriteString("        ignoreAtRules: ['tailwind', 'apply', 'layer', 'config', 'screen', 'variants', 'responsive', 'theme', 'utility', 'variant', 'custom-variant', 'source', 'plugin', 'reference'],\n")
+		b.WriteString("      },\n")
+		b.WriteString("    ],\n")
+		b.WriteString("    'function-no-unknown': [true, { ignoreFunctions: ['theme', 'screen'] }],\n")
+	}
+	b.WriteString("  },\n")
+	if f.styled {
+		b.WriteString("  overrides: [\n")
+		b.WriteString("    {\n")
+		b.WriteString("      files: ['**/*.{js,jsx,ts,tsx}'],\n")
+		b.WriteString("      customSyntax: 'postcss-styled-syntax',\n")
+		b.WriteString("    },\n")
+		b.WriteString("  ],\n")
+	}
+	b.WriteString("  ignoreFiles: ['build/**', 'coverage/**', 'dist/**', '.next/**', 'node_modules/**'],\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// stylelintConfigFile is the config written by setup stylelint.
+const stylelintConfigFile = `stylelint.config.mjs`
+
+// stylelintInstalls lists what setup stylelint installs under the workspace plan.
+func stylelintInstalls(plan workspacePlan) []installRequest {
+	return plan.installRequests("Stylelint and shared config", detectStyleFeatures().stylelintPackages(), false)
+}
+
+func setupStylelint() error {
+	currentTool = STYLELINT
+	reportInfo("Setting up stylelint...")
+
+	features := detectStyleFeatures()
+	reportInfo("Detected styles: scss=%t, tailwind=%t, styled-components=%t", features.scss, features.tailwind, features.styled)
+
+	pm, found := detectPackageManager()
+	if !found {
+		return errNoPackageManager
+	}
+	runCmdPrefix := pm.RunCmd
+
+	glob := features.styleGlob()
+	script := fmt.Sprintf("stylelint \"%s\" --fix", glob)
+	plan, err := askWorkspacePlan(STYLELINT)
+	if err != nil {
+		return err
+	}
+	errs := []error{runInstalls(pm, stylelintInstalls(plan))}
+
+	// Shared config and script at the root
+	if plan.atRoot() {
+		errs = append(errs, writeConfigFile(".", stylelintConfigFile, features.stylelintConfig()))
+		errs = append(errs, setPackageScripts(".", map[string]string{"lint:style": script}))
+	}
+
+	// Per-package configs, or package scripts delegating to the root config
+	for _, pkg := range plan.Packages {
+		if plan.Mode == workspacePackages {
+			errs = append(errs, writeConfigFile(pkg.Dir, stylelintConfigFile, features.stylelintConfig()))
+			errs = append(errs, setPackageScripts(pkg.Dir, map[string]string{"lint:style": script}))
+		} else {
+			errs = append(errs, setPackageScripts(pkg.Dir, map[string]string{"lint:style": fmt.Sprintf("%s --config %s", script, rootConfigPath(pkg.Dir, stylelintConfigFile))}))
+		}
+	}
+	errs = append(errs, plan.delegateFromRoot(pm, found, "lint:style"))
+
+	// Register the style sheets with lint-staged if it is configured
+	existingContent, err := os.ReadFile(lintStagedConfigFile)
+	if err == nil {
+		glob := features.styleSheetGlob()
+		if features.styled {
+			reportWarning("lint-staged only runs stylelint on %s: on the JS files it would race the JS linters, use '%s lint:style' for styled-components.", glob, runCmdPrefix)
+		}
+		content := string(existingContent)
+		entry := fmt.Sprintf("'%s'", glob)
+		marker := "export default {"
+		index := strings.Index(content, marker)
+		switch {
+		case strings.Contains(content, entry):
+			reportFileSkipped(lintStagedConfigFile, fmt.Sprintf("already handles %s", glob))
+		case index == -1:
+			reportWarning("Could not find the exported object in %s. Please add '%s': ['stylelint --fix'] manually.", lintStagedConfigFile, glob)
+		default:
+			index += len(marker)
+			line := fmt.Sprintf("\n  %s: ['stylelint --fix'],", entry)
+			newContent := content[:index] + line + content[index:]
+			err = os.WriteFile(lintStagedConfigFile, []byte(newContent), 0644)
+			if err != nil {
+				reportError("writing to %s: %v", lintStagedConfigFile, err)
+				errs = append(errs, &configError{Path: lintStagedConfigFile, Err: err})
+			} else {
+				emit(event{Type: eventFileWritten, Path: lintStagedConfigFile, Value: glob, Message: fmt.Sprintf("Style glob %s registered in %s.", glob, lintStagedConfigFile)})
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		reportError("checking for %s: %v", lintStagedConfigFile, err)
+		errs = append(errs, &configError{Path: lintStagedConfigFile, Err: err})
+	} else {
+		reportFileSkipped(lintStagedConfigFile, fmt.Sprintf("not found, skipping lint-staged integration (use '%s lint:style' manually)", runCmdPrefix))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	reportInfo("stylelint setup complete.")
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(stylelintCmd)
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import "testing"
+
+func TestStyleGlobs(t *testing.T) {
+	tests := []struct {
+		features        styleFeatures
+		style, lintable string
+	}{
+		{styleFeatures{}, "**/*.css", "**/*.css"},
+		{styleFeatures{scss: true}, "**/*.{css,scss}", "**/*.{css,scss}"},
+		{styleFeatures{styled: true}, "**/*.{css,js,jsx,ts,tsx}", "**/*.css"},
+		{styleFeatures{scss: true, styled: true}, "**/*.{css,scss,js,jsx,ts,tsx}", "**/*.{css,scss}"},
+	}
+	for _, tt := range tests {
+		if got := tt.features.styleGlob(); got != tt.style {
+			t.Errorf("%+v: styleGlob = %q, want %q", tt.features, got, tt.style)
+		}
+		// lint-staged must not see the JS files the JS linters handle
+		if got := tt.features.styleSheetGlob(); got != tt.lintable {
+			t.Errorf("%+v: styleSheetGlob = %q, want %q", tt.features, got, tt.lintable)
+		}
+	}
+}
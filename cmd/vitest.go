@@ -4,10 +4,8 @@ Copyright © 2024 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"os/exec"
 
 	"github.com/spf13/cobra"
 )
@@ -20,16 +18,23 @@ var vitestCmd = &cobra.Command{
 
 It installs Vitest, creates configuration files (vitest.config.ts and vitest.setup.ts),
 and adds a test script to your package.json. Vitest is a fast and lightweight testing
-framework for Vite-based projects.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		setupVitest()
+framework for Vite-based projects.
+
+Inside a monorepo it asks whether to set Vitest up at the workspace root, in
+each package, or both with package scripts delegating to the root config.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setupVitest()
 	},
 }
 
-func setupVitest() {
-	const vitestSetupFile = `vitest.setup.ts`
-	const vitestConfigFile = `vitest.config.ts`
-	const vitestConfig = `
+// Files written by setup vitest.
+const (
+	vitestSetupFile  = `vitest.setup.ts`
+	vitestConfigFile = `vitest.config.ts`
+)
+
+// vitestConfig is the vitest.config.ts written by setup vitest.
+const vitestConfig = `
 import path from 'path'
 import { defineConfig } from 'vitest/config'
 
@@ -41,111 +46,60 @@ export default defineConfig({
     },
     test: {
         environment: 'node',
-        setupFiles: ['./vitest.setup.ts'],
+        setupFiles: [path.join(__dirname, 'vitest.setup.ts')],
     },
 })
 `
-	const vitestSetupConfig = `
+
+const vitestSetupConfig = `
 import { afterEach } from 'vitest'
 
 afterEach(() => {})
 
 `
-	fmt.Println("vitest called")
-	// Create vitest.config.ts file
-	err := os.WriteFile(vitestConfigFile, []byte(vitestConfig), 0644)
-	if err != nil {
-		fmt.Printf("Error creating %s: %v\n", vitestConfigFile, err)
-	} else {
-		fmt.Printf("%s created successfully\n", vitestConfigFile)
-	}
 
-	// Create vitest.setup.ts file
-	err = os.WriteFile(vitestSetupFile, []byte(vitestSetupConfig), 0644)
-	if err != nil {
-		fmt.Printf("Error creating %s: %v\n", vitestSetupFile, err)
-	} else {
-		fmt.Printf("%s created successfully\n", vitestSetupFile)
-	}
+// vitestInstalls lists what setup vitest installs under the workspace plan.
+func vitestInstalls(plan workspacePlan) []installRequest {
+	return plan.installRequests("Vitest", []string{"vitest"}, false)
+}
 
-	// Define package managers and their commands
-	packageManagers := []struct {
-		name       string
-		installCmd []string
-	}{
-		{"pnpm", []string{"pnpm", "add", "-D", "vitest"}},
-		{"npm", []string{"npm", "install", "--save-dev", "vitest"}},
-		{"yarn", []string{"yarn", "add", "--dev", "vitest"}},
-		{"bun", []string{"bun", "add", "--dev", "vitest"}},
-	}
+func setupVitest() error {
+	currentTool = VITEST
+	reportInfo("vitest called")
 
-	var foundPackageManager string
-	var installCmdArgs []string
-
-	// Check for package managers in order
-	for _, pm := range packageManagers {
-		_, err := exec.LookPath(pm.name)
-		if err == nil {
-			foundPackageManager = pm.name
-			installCmdArgs = pm.installCmd
-			fmt.Printf("Found package manager: %s\n", foundPackageManager)
-			break // Use the first one found
-		}
+	var errs []error
+	pm, found := detectPackageManager()
+	if !found {
+		errs = append(errs, errNoPackageManager)
 	}
 
-	if foundPackageManager == "" {
-		fmt.Println("Error: No supported package manager (pnpm, npm, yarn, bun) found.")
-		fmt.Println("Please install one of these package managers and try again.")
-		// return
+	plan, err := askWorkspacePlan(VITEST)
+	if err != nil {
+		return err
+	}
+	if found {
+		errs = append(errs, runInstalls(pm, vitestInstalls(plan)))
 	}
 
-	// Run install command
-	if len(installCmdArgs) > 0 {
-		fmt.Printf("Running installation command: %s %v\n", installCmdArgs[0], installCmdArgs[1:])
-		installCmd := exec.Command(installCmdArgs[0], installCmdArgs[1:]...)
-		installCmd.Stdout = os.Stdout
-		installCmd.Stderr = os.Stderr
-		err = installCmd.Run()
-		if err != nil {
-			fmt.Printf("Error installing Vitest with %s: %v\n", foundPackageManager, err)
-		} else {
-			fmt.Println("Vitest installed successfully.")
-		}
-	} else if foundPackageManager != "" {
-		fmt.Printf("Package manager %s found, but no install command configured for Vitest.\n", foundPackageManager)
+	// Shared config and script at the root
+	if plan.atRoot() {
+		errs = append(errs, writeConfigFile(".", vitestConfigFile, vitestConfig))
+		errs = append(errs, writeConfigFile(".", vitestSetupFile, vitestSetupConfig))
+		errs = append(errs, setPackageScripts(".", map[string]string{"test": "vitest . "})) // or use detected package manager's run command
 	}
 
-	// Update package.json
-	packageJSONPath := "package.json"
-	packageJSONData, err := os.ReadFile(packageJSONPath)
-	if err != nil {
-		fmt.Printf("Error reading package.json: %v\n", err)
-	} else {
-		var pkgJSON map[string]interface{}
-		err = json.Unmarshal(packageJSONData, &pkgJSON)
-		if err != nil {
-			fmt.Printf("Error parsing package.json: %v\n", err)
+	// Per-package configs, or package scripts delegating to the root config
+	for _, pkg := range plan.Packages {
+		if plan.Mode == workspacePackages {
+			errs = append(errs, writeConfigFile(pkg.Dir, vitestConfigFile, vitestConfig))
+			errs = append(errs, writeConfigFile(pkg.Dir, vitestSetupFile, vitestSetupConfig))
+			errs = append(errs, setPackageScripts(pkg.Dir, map[string]string{"test": "vitest . "}))
 		} else {
-			scripts, ok := pkgJSON["scripts"].(map[string]interface{})
-			if !ok {
-				scripts = make(map[string]interface{})
-				pkgJSON["scripts"] = scripts
-			}
-			scripts["test"] = "vitest . " // or use detected package manager's run command
-
-			updatedData, err := json.MarshalIndent(pkgJSON, "", "  ")
-			if err != nil {
-				fmt.Printf("Error marshalling updated package.json: %v\n", err)
-			} else {
-				err = os.WriteFile(packageJSONPath, updatedData, 0644)
-				if err != nil {
-					fmt.Printf("Error writing updated package.json: %v\n", err)
-				} else {
-					fmt.Println("'test' script added/updated in package.json.")
-				}
-			}
+			errs = append(errs, setPackageScripts(pkg.Dir, map[string]string{"test": fmt.Sprintf("vitest --root . --config %s", rootConfigPath(pkg.Dir, vitestConfigFile))}))
 		}
 	}
+	errs = append(errs, plan.delegateFromRoot(pm, found, "test"))
+	return errors.Join(errs...)
 }
 
 func init() {
@@ -0,0 +1,271 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/CrossEvol/setup/common"
+	"github.com/charmbracelet/huh"
+)
+
+const (
+	workspaceRoot     = "root"
+	workspacePackages = "packages"
+	workspaceBoth     = "both"
+)
+
+// workspaceMode is the value of the global --workspace-mode flag.
+var workspaceMode string
+
+// latestVersions is the value of the global --latest flag.
+var latestVersions bool
+
+// offlineMode is the value of the global --offline flag.
+var offlineMode bool
+
+// workspacePlan tells a setup function where to install and configure a tool.
+type workspacePlan struct {
+	Mode     string
+	Packages []common.WorkspacePackage
+}
+
+// atRoot reports whether the tool is installed and configured at the root.
+func (p workspacePlan) atRoot() bool {
+	return p.Mode != workspacePackages
+}
+
+// workspacePlans remembers the answers of askWorkspacePlan, so 'setup node'
+// can plan the installs before the tools are set up without asking twice.
+var workspacePlans = make(map[string]workspacePlan)
+
+// askWorkspacePlan asks how a tool should be set up inside a monorepo. Outside
+// of a workspace the tool is simply set up in the current project.
+func askWorkspacePlan(tool string) (workspacePlan, error) {
+	if plan, ok := workspacePlans[tool]; ok {
+		return plan, nil
+	}
+	plan, err := promptWorkspacePlan(tool)
+	if err != nil {
+		return plan, err
+	}
+	workspacePlans[tool] = plan
+	return plan, nil
+}
+
+func promptWorkspacePlan(tool string) (workspacePlan, error) {
+	plan := workspacePlan{Mode: workspaceRoot}
+	if !common.IsWorkspaceRoot() {
+		return plan, nil
+	}
+	packages := common.WorkspacePackages()
+	if len(packages) == 0 {
+		return plan, nil
+	}
+
+	if workspaceMode != "" {
+		plan.Mode = workspaceMode
+		if plan.Mode != workspaceRoot {
+			plan.Packages = packages
+		}
+		return plan, nil
+	}
+
+	var packageOptions []huh.Option[string]
+	for _, pkg := range packages {
+		packageOptions = append(packageOptions, huh.NewOption(fmt.Sprintf("%s (%s)", pkg.Name, pkg.Dir), pkg.Dir).Selected(true))
+	}
+	var selected []string
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().Title(fmt.Sprintf("Set up %s in the workspace", tool)).
+				Options(
+					huh.NewOption("At the root, with a shared config", workspaceRoot),
+					huh.NewOption("In each package, with its own config", workspacePackages),
+					huh.NewOption("Both: shared root config, package scripts delegate to it", workspaceBoth),
+				).
+				Value(&plan.Mode),
+		),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().Title("Packages").
+				Options(packageOptions...).
+				Value(&selected),
+		).WithHideFunc(func() bool { return plan.Mode == workspaceRoot }),
+	)
+
+	err := form.Run()
+	if err != nil {
+		return plan, err
+	}
+
+	if plan.Mode != workspaceRoot {
+		for _, pkg := range packages {
+			for _, dir := range selected {
+				if pkg.Dir == dir {
+					plan.Packages = append(plan.Packages, pkg)
+				}
+			}
+		}
+	}
+	return plan, nil
+}
+
+// rootConfigPath returns the path of a root config file as seen from a package directory.
+func rootConfigPath(dir, file string) string {
+	rel, err := filepath.Rel(dir, file)
+	if err != nil {
+		return file
+	}
+	return filepath.ToSlash(rel)
+}
+
+// versionedPackages pins packages to the ranges of the version manifest,
+// unless --latest was given.
+func versionedPackages(packages []string) []string {
+	if latestVersions {
+		return packages
+	}
+	return common.DefaultVersionManifest().PinVersions(packages)
+}
+
+// projectPackageManager finds the package manager of the project, set up for
+// --offline.
+func projectPackageManager() (common.PackageManager, bool) {
+	pm, found := common.DetectPackageManager()
+	pm.Offline = offlineMode
+	return pm, found
+}
+
+// detectPackageManager finds the package manager of the project and reports it.
+func detectPackageManager() (common.PackageManager, bool) {
+	pm, found := projectPackageManager()
+	if !found {
+		reportError("No supported package manager (pnpm, npm, yarn, bun) found. Please install one of these package managers and try again.")
+		return pm, false
+	}
+	reportInfo("Found package manager: %s", pm.Name)
+	return pm, true
+}
+
+// runInstall runs an install command built by PackageManager.AddDevArgs.
+func runInstall(pm common.PackageManager, installCmdArgs []string, what string) error {
+	err := runCommand(installCmdArgs, fmt.Sprintf("Installing %s with %s", what, pm.Name))
+	if err != nil {
+		return &installError{What: "installing " + what, Err: err}
+	}
+	return nil
+}
+
+// writeConfigFile creates a config file inside dir.
+func writeConfigFile(dir, name, content string) error {
+	return writeFile(filepath.Join(dir, name), []byte(content), 0644)
+}
+
+// writeFile writes a file and reports it.
+func writeFile(path string, content []byte, perm os.FileMode) error {
+	err := os.WriteFile(path, content, perm)
+	if err != nil {
+		reportError("creating %s: %v", path, err)
+		return &configError{Path: path, Err: err}
+	}
+	reportFileWritten(path)
+	return nil
+}
+
+// updateFile writes a file that already existed, reporting it as updated.
+func updateFile(path string, content []byte, perm os.FileMode) error {
+	err := os.WriteFile(path, content, perm)
+	if err != nil {
+		reportError("updating %s: %v", path, err)
+		return &configError{Path: path, Err: err}
+	}
+	reportFileUpdated(path)
+	return nil
+}
+
+// setPackageScripts adds or updates scripts in the package.json inside dir.
+func setPackageScripts(dir string, scripts map[string]string) error {
+	packageJSONPath := filepath.Join(dir, "package.json")
+	packageJSONData, err := os.ReadFile(packageJSONPath)
+	if err != nil {
+		reportError("reading %s: %v", packageJSONPath, err)
+		return &configError{Path: packageJSONPath, Err: err}
+	}
+
+	var pkgJSON map[string]interface{}
+	err = json.Unmarshal(packageJSONData, &pkgJSON)
+	if err != nil {
+		reportError("parsing %s: %v", packageJSONPath, err)
+		return &configError{Path: packageJSONPath, Err: err}
+	}
+
+	existing, ok := pkgJSON["scripts"].(map[string]interface{})
+	if !ok {
+		existing = make(map[string]interface{})
+		pkgJSON["scripts"] = existing
+	}
+	var names []string
+	for name, value := range scripts {
+		existing[name] = value
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	updatedData, err := json.MarshalIndent(pkgJSON, "", "  ")
+	if err != nil {
+		reportError("marshalling updated %s: %v", packageJSONPath, err)
+		return &configError{Path: packageJSONPath, Err: err}
+	}
+	err = os.WriteFile(packageJSONPath, updatedData, 0644)
+	if err != nil {
+		reportError("writing updated %s: %v", packageJSONPath, err)
+		return &configError{Path: packageJSONPath, Err: err}
+	}
+	for _, name := range names {
+		reportScriptSet(packageJSONPath, name, scripts[name])
+	}
+	return nil
+}
+
+// workspaceRunScript returns the root command that runs a script in every package.
+func workspaceRunScript(pm common.PackageManager, script string) string {
+	switch common.WorkspaceRunner() {
+	case "turbo":
+		return "turbo run " + script
+	case "nx":
+		return "nx run-many -t " + script
+	}
+	switch pm.Name {
+	case "pnpm":
+		return "pnpm -r run " + script
+	case "yarn":
+		// foreach only exists since Yarn 2
+		if common.YarnClassic() {
+			return "yarn workspaces run " + script
+		}
+		return "yarn workspaces foreach -A run " + script
+	case "bun":
+		return "bun run --filter '*' " + script
+	default:
+		return "npm run " + script + " --workspaces --if-present"
+	}
+}
+
+// delegateFromRoot adds root scripts that run the per-package scripts when
+// the tool was only set up inside the packages.
+func (p workspacePlan) delegateFromRoot(pm common.PackageManager, found bool, scripts ...string) error {
+	if p.Mode != workspacePackages || len(p.Packages) == 0 || !found {
+		return nil
+	}
+	rootScripts := make(map[string]string)
+	for _, script := range scripts {
+		rootScripts[script] = workspaceRunScript(pm, script)
+	}
+	return setPackageScripts(".", rootScripts)
+}
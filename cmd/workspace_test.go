@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CrossEvol/setup/common"
+)
+
+func TestWorkspaceRunScriptYarn(t *testing.T) {
+	t.Chdir(t.TempDir())
+	bin := t.TempDir()
+	t.Setenv("PATH", bin)
+	yarn := common.PackageManager{Name: "yarn"}
+
+	tests := []struct {
+		version, want string
+	}{
+		{"1.22.22", "yarn workspaces run lint"},
+		{"4.5.0", "yarn workspaces foreach -A run lint"},
+	}
+	for _, tt := range tests {
+		script := "#!/bin/sh\necho " + tt.version + "\n"
+		if err := os.WriteFile(filepath.Join(bin, "yarn"), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if got := workspaceRunScript(yarn, "lint"); got != tt.want {
+			t.Errorf("yarn %s: workspaceRunScript = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,539 @@
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Rule levels as used by commitlint: 0 disables a rule, 1 warns, 2 errors.
+const (
+	LevelDisabled = 0
+	LevelWarning  = 1
+	LevelError    = 2
+)
+
+// Rule is a commitlint rule configuration: [level, when, value].
+type Rule struct {
+	Level int
+	When  string
+	Value any // string, int, []string or nil
+}
+
+// Rules maps rule names to their configuration.
+type Rules map[string]Rule
+
+// Problem is a rule violation.
+type Problem struct {
+	Level   int
+	Name    string
+	Message string
+}
+
+var ruleStart = regexp.MustCompile(`['"]([a-z-]+)['"]\s*:\s*\[`)
+
+// conventionalRules are the rules of @commitlint/config-conventional, which a
+// config extending it starts from.
+var conventionalRules = Rules{
+	"body-leading-blank":     {Level: LevelWarning, When: "always"},
+	"body-max-line-length":   {Level: LevelError, When: "always", Value: 100},
+	"footer-leading-blank":   {Level: LevelWarning, When: "always"},
+	"footer-max-line-length": {Level: LevelError, When: "always", Value: 100},
+	"header-max-length":      {Level: LevelError, When: "always", Value: 100},
+	"header-trim":            {Level: LevelError, When: "always"},
+	"subject-case":           {Level: LevelError, When: "never", Value: []string{"sentence-case", "start-case", "pascal-case", "upper-case"}},
+	"subject-empty":          {Level: LevelError, When: "never"},
+	"subject-full-stop":      {Level: LevelError, When: "never", Value: "."},
+	"type-case":              {Level: LevelError, When: "always", Value: "lower-case"},
+	"type-empty":             {Level: LevelError, When: "never"},
+	"type-enum": {Level: LevelError, When: "always", Value: []string{
+		"build", "chore", "ci", "docs", "feat", "fix", "perf", "refactor", "revert", "style", "test"}},
+}
+
+var extendsConventional = regexp.MustCompile(`extends\s*:\s*\[[^\]]*['"]@commitlint/config-conventional['"]`)
+
+// ParseCommitlintRules reads the "rules" object of a commitlint config such as
+// the commitlint.config.cjs written by 'setup commitlint', on top of the rules
+// of @commitlint/config-conventional when the config extends it. Only literal
+// rule values are understood, which covers every generated rule.
+func ParseCommitlintRules(content string) Rules {
+	content = stripJSComments(content)
+	rules := make(Rules)
+	if extendsConventional.MatchString(content) {
+		for name, rule := range conventionalRules {
+			rules[name] = rule
+		}
+	}
+
+	for _, loc := range ruleStart.FindAllStringSubmatchIndex(content, -1) {
+		name := content[loc[2]:loc[3]]
+		open := loc[1] - 1
+		end := matchingBracket(content, open)
+		if end == -1 {
+			continue
+		}
+		values := splitTopLevel(content[open+1 : end])
+		if len(values) == 0 {
+			continue
+		}
+
+		level, err := strconv.Atoi(values[0])
+		if err != nil {
+			continue
+		}
+		rule := Rule{Level: level}
+		if len(values) > 1 {
+			rule.When = unquote(values[1])
+		}
+		if len(values) > 2 {
+			rule.Value = parseJSValue(values[2])
+		}
+		rules[name] = rule
+	}
+	return rules
+}
+
+// stripJSComments removes // and /* */ comments outside of string literals.
+func stripJSComments(content string) string {
+	var b strings.Builder
+	var quote byte
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case quote != 0:
+			b.WriteByte(c)
+			if c == '\\' && i+1 < len(content) {
+				i++
+				b.WriteByte(content[i])
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			b.WriteByte(c)
+		case c == '/' && i+1 < len(content) && content[i+1] == '/':
+			for i < len(content) && content[i] != '\n' {
+				i++
+			}
+			b.WriteByte('\n')
+		case c == '/' && i+1 < len(content) && content[i+1] == '*':
+			end := strings.Index(content[i+2:], "*/")
+			if end == -1 {
+				return b.String()
+			}
+			i += end + 3
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// matchingBracket returns the index of the bracket closing the one at open.
+func matchingBracket(content string, open int) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits an array body on the commas that are not nested.
+func splitTopLevel(body string) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(body[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(body[start:]); last != "" {
+		parts = append(parts, last)
+	}
+	return parts
+}
+
+func unquote(value string) string {
+	return strings.Trim(strings.TrimSpace(value), `'"`)
+}
+
+// parseJSValue converts a literal to a Go value: numbers to int, arrays to []string.
+func parseJSValue(value string) any {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "[") {
+		var list []string
+		for _, item := range splitTopLevel(strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")) {
+			list = append(list, unquote(item))
+		}
+		return list
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		return n
+	}
+	return unquote(value)
+}
+
+// IntValue returns the numeric value of a rule, or fallback when it has none.
+func (r Rule) IntValue(fallback int) int {
+	if n, ok := r.Value.(int); ok {
+		return n
+	}
+	return fallback
+}
+
+// ListValue returns the list value of a rule. A single string is returned as a one-item list.
+func (r Rule) ListValue() []string {
+	switch v := r.Value.(type) {
+	case []string:
+		return v
+	case string:
+		return []string{v}
+	}
+	return nil
+}
+
+// Enabled reports whether the rule is configured and not disabled.
+func (rules Rules) Enabled(name string) (Rule, bool) {
+	rule, ok := rules[name]
+	return rule, ok && rule.Level > LevelDisabled
+}
+
+// check records a problem when a rule is enabled and its condition does not hold.
+// holds is the result of the "always" form of the rule.
+func (rules Rules) check(problems []Problem, name string, holds bool, message string) []Problem {
+	rule, ok := rules.Enabled(name)
+	if !ok {
+		return problems
+	}
+	if rule.When == "never" {
+		holds = !holds
+	}
+	if !holds {
+		problems = append(problems, Problem{Level: rule.Level, Name: name, Message: message})
+	}
+	return problems
+}
+
+// FormatHeader builds "type(scope)!: subject".
+func FormatHeader(commitType, scope string, breaking bool, subject string) string {
+	header := commitType
+	if scope != "" {
+		header += "(" + scope + ")"
+	}
+	if breaking {
+		header += "!"
+	}
+	return header + ": " + subject
+}
+
+// LintHeader checks the header rules against an already split header.
+func LintHeader(rules Rules, commitType, scope, subject, header string) []Problem {
+	var problems []Problem
+
+	if rule, ok := rules.Enabled("type-enum"); ok && commitType != "" {
+		allowed := rule.ListValue()
+		problems = rules.check(problems, "type-enum", contains(allowed, commitType),
+			fmt.Sprintf("type must %sbe one of [%s]", negation(rule), strings.Join(allowed, ", ")))
+	}
+	problems = rules.check(problems, "type-empty", commitType == "", "type may not be empty")
+	if commitType != "" {
+		if rule, ok := rules.Enabled("type-case"); ok {
+			problems = rules.check(problems, "type-case", matchesCase(commitType, rule.ListValue()),
+				fmt.Sprintf("type must %sbe %s", negation(rule), strings.Join(rule.ListValue(), ", ")))
+		}
+	}
+
+	if rule, ok := rules.Enabled("scope-enum"); ok && scope != "" && len(rule.ListValue()) > 0 {
+		allowed := rule.ListValue()
+		problems = rules.check(problems, "scope-enum", contains(allowed, scope),
+			fmt.Sprintf("scope must %sbe one of [%s]", negation(rule), strings.Join(allowed, ", ")))
+	}
+	problems = rules.check(problems, "scope-empty", scope == "", "scope may not be empty")
+	if scope != "" {
+		if rule, ok := rules.Enabled("scope-case"); ok {
+			problems = rules.check(problems, "scope-case", matchesCase(scope, rule.ListValue()),
+				fmt.Sprintf("scope must %sbe %s", negation(rule), strings.Join(rule.ListValue(), ", ")))
+		}
+	}
+
+	problems = rules.check(problems, "subject-empty", subject == "", "subject may not be empty")
+	if subject != "" {
+		if rule, ok := rules.Enabled("subject-case"); ok {
+			problems = rules.check(problems, "subject-case", matchesCase(subject, rule.ListValue()),
+				fmt.Sprintf("subject must %sbe %s", negation(rule), strings.Join(rule.ListValue(), ", ")))
+		}
+		if rule, ok := rules.Enabled("subject-full-stop"); ok {
+			stop := "."
+			if s, isString := rule.Value.(string); isString {
+				stop = s
+			}
+			problems = rules.check(problems, "subject-full-stop", strings.HasSuffix(subject, stop),
+				fmt.Sprintf("subject may %send with full stop", negation(rule)))
+		}
+	}
+
+	if rule, ok := rules.Enabled("header-max-length"); ok {
+		max := rule.IntValue(100)
+		if len([]rune(header)) > max {
+			problems = append(problems, Problem{Level: rule.Level, Name: "header-max-length",
+				Message: fmt.Sprintf("header must not be longer than %d characters, current length is %d", max, len([]rune(header)))})
+		}
+	}
+	if rule, ok := rules.Enabled("header-min-length"); ok {
+		min := rule.IntValue(0)
+		if len([]rune(header)) < min {
+			problems = append(problems, Problem{Level: rule.Level, Name: "header-min-length",
+				Message: fmt.Sprintf("header must not be shorter than %d characters, current length is %d", min, len([]rune(header)))})
+		}
+	}
+
+	return problems
+}
+
+// negation renders the "not " of a rule configured with "never".
+func negation(rule Rule) string {
+	if rule.When == "never" {
+		return "not "
+	}
+	return ""
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesCase reports whether value is written in any of the given commitlint cases.
+func matchesCase(value string, cases []string) bool {
+	for _, c := range cases {
+		var ok bool
+		switch c {
+		case "lower-case", "lowercase":
+			ok = value == strings.ToLower(value)
+		case "upper-case", "uppercase":
+			ok = value == strings.ToUpper(value)
+		case "sentence-case", "sentencecase":
+			first := []rune(value)[0]
+			ok = !unicode.IsLower(first) && value[len(string(first)):] == strings.ToLower(value[len(string(first)):])
+		case "start-case", "startcase", "pascal-case", "pascalcase":
+			ok = true
+			for _, word := range strings.FieldsFunc(value, func(r rune) bool { return r == ' ' || r == '-' || r == '_' }) {
+				ok = ok && !unicode.IsLower([]rune(word)[0])
+			}
+		case "camel-case", "camelcase":
+			ok = !unicode.IsUpper([]rune(value)[0]) && !strings.ContainsAny(value, " -_")
+		case "kebab-case", "kebabcase":
+			ok = value == strings.ToLower(value) && !strings.ContainsAny(value, " _")
+		case "snake-case", "snakecase":
+			ok = value == strings.ToLower(value) && !strings.ContainsAny(value, " -")
+		default:
+			ok = true
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CommitMessage is a commit message split into its conventional parts.
+type CommitMessage struct {
+	Raw      string
+	Header   string
+	Type     string
+	Scope    string
+	Subject  string
+	Breaking bool
+	Body     string
+	Footer   string
+
+	// BodyLeadingBlank and FooterLeadingBlank tell whether a blank line
+	// precedes the body and the footer.
+	BodyLeadingBlank   bool
+	FooterLeadingBlank bool
+}
+
+var (
+	headerPattern = regexp.MustCompile(`^(\w*)(?:\(([^()]*)\))?(!)?: (.*)$`)
+	footerPattern = regexp.MustCompile(`^(BREAKING[ -]CHANGE|[\w-]+)(: | #)`)
+	ignorePattern = regexp.MustCompile(`^((Merge pull request)|(Merge (.*?) into (.*?)|(Merge branch (.*?)))(?:\r?\n)*$)|^(Merge tag (.*?))(?:\r?\n)*$|^(R|r)evert (.*)|^(amend|fixup|squash)!|^(Merged (.*?)(in|into) (.*))|^Merge remote-tracking branch(\s*)(.*)|^Automatic merge(.*)|^Auto-merged (.*?) into (.*)`)
+)
+
+// ParseCommitMessage splits a raw message the way the conventional-commits
+// parser used by commitlint does. Comment lines and everything below the
+// scissors line of 'git commit --verbose' are dropped.
+func ParseCommitMessage(raw string) CommitMessage {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, "# ------------------------ >8 ------------------------") {
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	msg := CommitMessage{Raw: strings.Join(lines, "\n")}
+	if len(lines) == 0 {
+		return msg
+	}
+
+	msg.Header = lines[0]
+	if match := headerPattern.FindStringSubmatch(msg.Header); match != nil {
+		msg.Type, msg.Scope, msg.Breaking, msg.Subject = match[1], match[2], match[3] == "!", match[4]
+	}
+
+	rest := lines[1:]
+	msg.BodyLeadingBlank = len(rest) == 0 || strings.TrimSpace(rest[0]) == ""
+
+	footerStart := -1
+	for i, line := range rest {
+		if footerPattern.MatchString(line) {
+			footerStart = i
+			break
+		}
+	}
+
+	bodyLines := rest
+	if footerStart != -1 {
+		bodyLines = rest[:footerStart]
+		msg.Footer = strings.TrimSpace(strings.Join(rest[footerStart:], "\n"))
+		msg.FooterLeadingBlank = footerStart > 0 && strings.TrimSpace(rest[footerStart-1]) == ""
+		if strings.Contains(msg.Footer, "BREAKING CHANGE") || strings.Contains(msg.Footer, "BREAKING-CHANGE") {
+			msg.Breaking = true
+		}
+	}
+	msg.Body = strings.TrimSpace(strings.Join(bodyLines, "\n"))
+
+	return msg
+}
+
+// IsIgnoredMessage reports whether commitlint skips the message by default
+// (merges, reverts, fixup! and squash! commits).
+func IsIgnoredMessage(raw string) bool {
+	return ignorePattern.MatchString(strings.TrimSpace(raw))
+}
+
+// LintMessage checks a parsed message against the rules.
+func LintMessage(rules Rules, msg CommitMessage) []Problem {
+	problems := LintHeader(rules, msg.Type, msg.Scope, msg.Subject, msg.Header)
+
+	if rule, ok := rules.Enabled("header-full-stop"); ok {
+		stop := "."
+		if s, isString := rule.Value.(string); isString {
+			stop = s
+		}
+		problems = rules.check(problems, "header-full-stop", strings.HasSuffix(msg.Header, stop),
+			fmt.Sprintf("header must %send with full stop", negation(rule)))
+	}
+	if rule, ok := rules.Enabled("header-trim"); ok && rule.When != "never" {
+		problems = rules.check(problems, "header-trim", msg.Header == strings.TrimSpace(msg.Header),
+			"header must not be surrounded by whitespace")
+	}
+
+	if msg.Body != "" {
+		problems = rules.check(problems, "body-leading-blank", msg.BodyLeadingBlank, "body must have leading blank line")
+		problems = lintLength(rules, problems, "body-max-length", "body", msg.Body, true)
+		problems = lintLength(rules, problems, "body-min-length", "body", msg.Body, false)
+		problems = lintLineLength(rules, problems, "body-max-line-length", "body", msg.Body)
+		if rule, ok := rules.Enabled("body-full-stop"); ok {
+			stop := "."
+			if s, isString := rule.Value.(string); isString {
+				stop = s
+			}
+			problems = rules.check(problems, "body-full-stop", strings.HasSuffix(msg.Body, stop),
+				fmt.Sprintf("body may %send with full stop", negation(rule)))
+		}
+	}
+	problems = rules.check(problems, "body-empty", msg.Body == "", "body may not be empty")
+
+	if msg.Footer != "" {
+		problems = rules.check(problems, "footer-leading-blank", msg.FooterLeadingBlank, "footer must have leading blank line")
+		problems = lintLength(rules, problems, "footer-max-length", "footer", msg.Footer, true)
+		problems = lintLength(rules, problems, "footer-min-length", "footer", msg.Footer, false)
+		problems = lintLineLength(rules, problems, "footer-max-line-length", "footer", msg.Footer)
+	}
+	problems = rules.check(problems, "footer-empty", msg.Footer == "", "footer may not be empty")
+	problems = rules.check(problems, "references-empty", !strings.Contains(msg.Raw, "#"), "references may not be empty")
+
+	return problems
+}
+
+// lintLength checks a *-max-length or *-min-length rule.
+func lintLength(rules Rules, problems []Problem, name, part, text string, max bool) []Problem {
+	rule, ok := rules.Enabled(name)
+	if !ok {
+		return problems
+	}
+	limit := rule.IntValue(0)
+	length := len([]rune(text))
+	if max && length > limit {
+		return append(problems, Problem{Level: rule.Level, Name: name,
+			Message: fmt.Sprintf("%s must not be longer than %d characters, current length is %d", part, limit, length)})
+	}
+	if !max && length < limit {
+		return append(problems, Problem{Level: rule.Level, Name: name,
+			Message: fmt.Sprintf("%s must not be shorter than %d characters, current length is %d", part, limit, length)})
+	}
+	return problems
+}
+
+// lintLineLength checks a *-max-line-length rule. URLs are exempt, as in commitlint.
+func lintLineLength(rules Rules, problems []Problem, name, part, text string) []Problem {
+	rule, ok := rules.Enabled(name)
+	if !ok {
+		return problems
+	}
+	limit := rule.IntValue(100)
+	for _, line := range strings.Split(text, "\n") {
+		if len([]rune(line)) > limit && !strings.Contains(line, "://") {
+			return append(problems, Problem{Level: rule.Level, Name: name,
+				Message: fmt.Sprintf("%s's lines must not be longer than %d characters", part, limit)})
+		}
+	}
+	return problems
+}
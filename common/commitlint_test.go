@@ -0,0 +1,80 @@
+package common
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCommitlintRules(t *testing.T) {
+	rules := ParseCommitlintRules(`module.exports = {
+  extends: ['@commitlint/config-conventional'],
+  rules: {
+    // comments are skipped: 'scope-empty': [2, 'always'],
+    'type-enum': [2, 'always', ['feat', 'fix']],
+    'header-max-length': [1, 'always', 72], /* tighter than the preset */
+    'subject-case': [0],
+  },
+};`)
+
+	tests := []struct {
+		name string
+		want Rule
+	}{
+		{"type-enum", Rule{LevelError, "always", []string{"feat", "fix"}}},
+		{"header-max-length", Rule{LevelWarning, "always", 72}},
+		{"subject-case", Rule{Level: LevelDisabled}},
+		// Not set by the config, so the preset applies
+		{"body-max-line-length", Rule{LevelError, "always", 100}},
+		{"footer-max-line-length", Rule{LevelError, "always", 100}},
+		{"header-trim", Rule{LevelError, "always", nil}},
+		{"subject-full-stop", Rule{LevelError, "never", "."}},
+	}
+	for _, tt := range tests {
+		if got := rules[tt.name]; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+	if _, ok := rules["scope-empty"]; ok {
+		t.Error("scope-empty was read from a comment")
+	}
+}
+
+func TestParseCommitlintRulesWithoutPreset(t *testing.T) {
+	rules := ParseCommitlintRules(`export default { rules: { 'header-max-length': [2, 'always', 50] } };`)
+	if len(rules) != 1 || rules["header-max-length"].IntValue(0) != 50 {
+		t.Errorf("rules = %+v, want header-max-length only", rules)
+	}
+}
+
+func TestLintMessage(t *testing.T) {
+	rules := ParseCommitlintRules(`module.exports = { extends: ['@commitlint/config-conventional'] };`)
+	long := strings.Repeat("word ", 21) // 105 characters
+
+	tests := []struct {
+		name, message string
+		want          []string
+	}{
+		{"valid", "fix: handle empty input\n\nThe parser returned early.", nil},
+		{"unknown type", "feature: add a flag", []string{"type-enum"}},
+		{"full stop", "fix: handle empty input.", []string{"subject-full-stop"}},
+		{"sentence case", "fix: Handle empty input", []string{"subject-case"}},
+		{"missing subject", "fix:", []string{"type-empty", "subject-empty"}},
+		{"header whitespace", "fix: handle empty input ", []string{"header-trim"}},
+		{"no blank line", "fix: handle empty input\nThe parser returned early.", []string{"body-leading-blank"}},
+		{"long body line", "fix: handle empty input\n\n" + long, []string{"body-max-line-length"}},
+		{"long footer line", "fix: handle empty input\n\nRefs: " + long, []string{"footer-max-line-length"}},
+		{"urls are exempt", "fix: handle empty input\n\nSee https://example.com/" + long, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, problem := range LintMessage(rules, ParseCommitMessage(tt.message)) {
+				got = append(got, problem.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("problems = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
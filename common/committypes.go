@@ -0,0 +1,127 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/CrossEvol/setup/assets"
+)
+
+// ChangelogSection holds the changelog heading of a commit type.
+type ChangelogSection struct {
+	Emoji string `json:"emoji"`
+	En    string `json:"en"`
+	Zh    string `json:"zh"`
+}
+
+// CommitType is an entry of the conventional commit type catalog shared by
+// the commitlint and release-it generators.
+type CommitType struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Section     ChangelogSection `json:"section"`
+	Hidden      bool             `json:"hidden"`
+}
+
+// DefaultCommitTypes returns the embedded commit type catalog.
+func DefaultCommitTypes() []CommitType {
+	var types []CommitType
+	if err := json.Unmarshal(assets.CommitTypes, &types); err != nil {
+		// The catalog is embedded at build time, so this is a programming error
+		panic(fmt.Sprintf("invalid commit type catalog: %v", err))
+	}
+	return types
+}
+
+// FindCommitType looks a type up by name.
+func FindCommitType(types []CommitType, name string) (CommitType, bool) {
+	for _, t := range types {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return CommitType{}, false
+}
+
+var typeEnumEntry = regexp.MustCompile(`^'([^']+)',?\s*(?://\s*(.*))?$`)
+
+// TypeEnumRange locates the list of allowed types inside the 'type-enum' rule
+// of a commitlint config. It returns the offsets of the brackets enclosing
+// the list, or ok == false when the rule is missing.
+func TypeEnumRange(content string) (start, end int, ok bool) {
+	rule := strings.Index(content, `'type-enum'`)
+	if rule == -1 {
+		return 0, 0, false
+	}
+	// The rule is [level, 'always', [ ...types ]], so the list is the second '['
+	outer := strings.Index(content[rule:], "[")
+	if outer == -1 {
+		return 0, 0, false
+	}
+	inner := strings.Index(content[rule+outer+1:], "[")
+	if inner == -1 {
+		return 0, 0, false
+	}
+	start = rule + outer + 1 + inner
+	end = strings.Index(content[start:], "]")
+	if end == -1 {
+		return 0, 0, false
+	}
+	return start, start + end, true
+}
+
+// ParseTypeEnum reads the allowed types of a commitlint config. The trailing
+// comment of an entry is used as its description.
+func ParseTypeEnum(content string) ([]CommitType, bool) {
+	start, end, ok := TypeEnumRange(content)
+	if !ok {
+		return nil, false
+	}
+
+	var types []CommitType
+	for _, line := range strings.Split(content[start+1:end], "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		// Entries may also be written inline: 'feat', 'fix'
+		if match := typeEnumEntry.FindStringSubmatch(line); match != nil {
+			types = append(types, CommitType{Name: match[1], Description: strings.TrimSpace(match[2])})
+			continue
+		}
+		for _, part := range strings.Split(line, ",") {
+			part = strings.Trim(strings.TrimSpace(part), `'"`)
+			if part != "" {
+				types = append(types, CommitType{Name: part})
+			}
+		}
+	}
+	return types, true
+}
+
+// RenderTypeEnum renders the bracketed list of allowed types, indented for
+// the generated commitlint config.
+func RenderTypeEnum(types []CommitType) string {
+	var b strings.Builder
+	b.WriteString("[\n")
+	for _, t := range types {
+		if t.Description != "" {
+			fmt.Fprintf(&b, "        '%s', // %s\n", t.Name, t.Description)
+		} else {
+			fmt.Fprintf(&b, "        '%s',\n", t.Name)
+		}
+	}
+	b.WriteString("      ]")
+	return b.String()
+}
+
+// ReplaceTypeEnum swaps the allowed types of a commitlint config.
+func ReplaceTypeEnum(content string, types []CommitType) (string, bool) {
+	start, end, ok := TypeEnumRange(content)
+	if !ok {
+		return content, false
+	}
+	return content[:start] + RenderTypeEnum(types) + content[end+1:], true
+}
@@ -0,0 +1,64 @@
+package common
+
+import (
+	"path/filepath"
+	"slices"
+)
+
+// Ecosystem is a language, build tool or editor a project can use.
+type Ecosystem struct {
+	Name      string
+	Markers   []string // files, directories or globs whose presence reveals it
+	Gitignore []string // github/gitignore templates it needs
+}
+
+// Ecosystems lists the ecosystems DetectEcosystems recognizes.
+var Ecosystems = []Ecosystem{
+	{Name: "Go", Markers: []string{"go.mod"}, Gitignore: []string{"Go"}},
+	{Name: "Node.js", Markers: []string{"package.json"}, Gitignore: []string{"Node"}},
+	{Name: "Rust", Markers: []string{"Cargo.toml"}, Gitignore: []string{"Rust"}},
+	{Name: "Python", Markers: []string{"pyproject.toml", "requirements.txt"}, Gitignore: []string{"Python"}},
+	{Name: "Maven", Markers: []string{"pom.xml"}, Gitignore: []string{"Java", "Maven"}},
+	{Name: "Gradle", Markers: []string{"build.gradle", "build.gradle.kts"}, Gitignore: []string{"Java", "Gradle"}},
+	{Name: ".NET", Markers: []string{"*.csproj"}, Gitignore: []string{"VisualStudio"}},
+	{Name: "CMake", Markers: []string{"CMakeLists.txt"}, Gitignore: []string{"C++", "CMake"}},
+	{Name: "Ruby", Markers: []string{"Gemfile"}, Gitignore: []string{"Ruby"}},
+	{Name: "PHP", Markers: []string{"composer.json"}, Gitignore: []string{"Composer"}},
+	{Name: "JetBrains IDEs", Markers: []string{".idea"}, Gitignore: []string{"Global/JetBrains"}},
+	{Name: "Visual Studio Code", Markers: []string{".vscode"}, Gitignore: []string{"Global/VisualStudioCode"}},
+}
+
+// DetectedEcosystem is an ecosystem found in a project, with the marker that revealed it.
+type DetectedEcosystem struct {
+	Ecosystem
+	Marker string
+}
+
+// DetectEcosystems returns the ecosystems whose markers exist in dir.
+func DetectEcosystems(dir string) []DetectedEcosystem {
+	var detected []DetectedEcosystem
+	for _, ecosystem := range Ecosystems {
+		for _, marker := range ecosystem.Markers {
+			matches, err := filepath.Glob(filepath.Join(dir, marker))
+			if err == nil && len(matches) > 0 {
+				detected = append(detected, DetectedEcosystem{Ecosystem: ecosystem, Marker: filepath.Base(matches[0])})
+				break
+			}
+		}
+	}
+	return detected
+}
+
+// RecommendedGitignoreTemplates returns the templates the detected
+// ecosystems need, without duplicates.
+func RecommendedGitignoreTemplates(detected []DetectedEcosystem) []string {
+	var templates []string
+	for _, ecosystem := range detected {
+		for _, template := range ecosystem.Gitignore {
+			if !slices.Contains(templates, template) {
+				templates = append(templates, template)
+			}
+		}
+	}
+	return templates
+}
@@ -0,0 +1,246 @@
+package common
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/CrossEvol/setup/assets"
+)
+
+// GitignoreTemplates returns the embedded github/gitignore templates, keyed by
+// their path such as "Go.gitignore" or "Global/macOS.gitignore".
+func GitignoreTemplates() (map[string]string, error) {
+	return ParseGitignoreBundle(assets.GitignoreBundle)
+}
+
+// ParseGitignoreBundle reads a bundle written by 'make collect': a gzipped
+// JSON object mapping template paths to their content.
+func ParseGitignoreBundle(bundle []byte) (map[string]string, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(bundle))
+	if err != nil {
+		return nil, fmt.Errorf("opening the gitignore bundle: %w", err)
+	}
+	defer reader.Close()
+
+	var templates map[string]string
+	if err := json.NewDecoder(reader).Decode(&templates); err != nil {
+		return nil, fmt.Errorf("reading the gitignore bundle: %w", err)
+	}
+	return templates, nil
+}
+
+// Markers around the blocks of a .gitignore managed by setup ignore, followed
+// by the template name: "# >>> setup:Go" ... "# <<< setup:Go".
+const (
+	GitignoreBlockStart = "# >>> setup:"
+	GitignoreBlockEnd   = "# <<< setup:"
+)
+
+// GitignorePart is a run of lines of a .gitignore: either a managed block of
+// a template, or lines written by hand when Template is empty.
+type GitignorePart struct {
+	Template string
+	Lines    []string
+}
+
+// GitignoreFile is a .gitignore split into hand-written lines and managed blocks.
+type GitignoreFile struct {
+	Parts []GitignorePart
+}
+
+// ParseGitignore splits a .gitignore into its parts. A block without its end
+// marker, before another start marker of its template, is treated as
+// hand-written.
+func ParseGitignore(content string) *GitignoreFile {
+	file := &GitignoreFile{}
+	content = strings.TrimSuffix(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if content == "" {
+		return file
+	}
+
+	lines := strings.Split(content, "\n")
+	var manual []string
+	for i := 0; i < len(lines); i++ {
+		name, ok := strings.CutPrefix(lines[i], GitignoreBlockStart)
+		end := -1
+		if ok {
+			end = slices.Index(lines[i+1:], GitignoreBlockEnd+name)
+			if end != -1 && slices.Contains(lines[i+1:i+1+end], lines[i]) {
+				end = -1
+			}
+		}
+		if end == -1 {
+			manual = append(manual, lines[i])
+			continue
+		}
+		if len(manual) > 0 {
+			file.Parts = append(file.Parts, GitignorePart{Lines: manual})
+			manual = nil
+		}
+		file.Parts = append(file.Parts, GitignorePart{Template: name, Lines: slices.Clone(lines[i+1 : i+1+end])})
+		i += end + 1
+	}
+	if len(manual) > 0 {
+		file.Parts = append(file.Parts, GitignorePart{Lines: manual})
+	}
+	return file
+}
+
+// Applied returns the templates of the managed blocks, in file order.
+func (f *GitignoreFile) Applied() []string {
+	var templates []string
+	for _, part := range f.Parts {
+		if part.Template != "" {
+			templates = append(templates, part.Template)
+		}
+	}
+	return templates
+}
+
+// Block returns the lines of the managed block of a template.
+func (f *GitignoreFile) Block(template string) ([]string, bool) {
+	for _, part := range f.Parts {
+		if part.Template == template {
+			return part.Lines, true
+		}
+	}
+	return nil, false
+}
+
+// SetBlock replaces the managed block of a template with its content, or
+// appends a new block. Patterns already listed above the block are left out;
+// comments and blank lines are kept.
+func (f *GitignoreFile) SetBlock(template, content string) {
+	index := slices.IndexFunc(f.Parts, func(part GitignorePart) bool {
+		return part.Template == template
+	})
+	if index == -1 {
+		// Keep a blank line between the existing content and the new block
+		if len(f.Parts) > 0 {
+			last := f.Parts[len(f.Parts)-1]
+			if last.Template != "" || strings.TrimSpace(last.Lines[len(last.Lines)-1]) != "" {
+				f.Parts = append(f.Parts, GitignorePart{Lines: []string{""}})
+			}
+		}
+		f.Parts = append(f.Parts, GitignorePart{Template: template})
+		index = len(f.Parts) - 1
+	}
+
+	seen := make(map[string]bool)
+	for _, part := range f.Parts[:index] {
+		for _, line := range part.Lines {
+			if pattern := gitignorePattern(line); pattern != "" {
+				seen[pattern] = true
+			}
+		}
+	}
+
+	var lines []string
+	content = strings.TrimRight(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for _, line := range strings.Split(content, "\n") {
+		if pattern := gitignorePattern(line); pattern != "" {
+			if seen[pattern] {
+				continue
+			}
+			seen[pattern] = true
+		}
+		lines = append(lines, line)
+	}
+	f.Parts[index].Lines = lines
+}
+
+// gitignorePattern returns the pattern of a line, or "" for comments and blank lines.
+func gitignorePattern(line string) string {
+	pattern := strings.TrimSpace(line)
+	if strings.HasPrefix(pattern, "#") {
+		return ""
+	}
+	return pattern
+}
+
+// String renders the file with the markers around the managed blocks.
+func (f *GitignoreFile) String() string {
+	var b strings.Builder
+	for _, part := range f.Parts {
+		if part.Template != "" {
+			b.WriteString(GitignoreBlockStart + part.Template + "\n")
+		}
+		for _, line := range part.Lines {
+			b.WriteString(line + "\n")
+		}
+		if part.Template != "" {
+			b.WriteString(GitignoreBlockEnd + part.Template + "\n")
+		}
+	}
+	return b.String()
+}
+
+// DiffLines returns the lines removed from a ("-" prefix) and added in b
+// ("+" prefix), in order, based on their longest common subsequence.
+func DiffLines(a, b []string) []string {
+	// lcs[i][j] is the length of the common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, "-"+a[i])
+			i++
+		default:
+			diff = append(diff, "+"+b[j])
+			j++
+		}
+	}
+	return diff
+}
+
+// GitignorePatterns returns the patterns of a .gitignore in order, without
+// comments and blank lines.
+func GitignorePatterns(content string) []string {
+	var patterns []string
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if pattern := gitignorePattern(line); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// AnchoredGlob rewrites a .gitignore pattern for tools whose patterns are
+// relative to the root, like Docker and ESLint: a pattern without a slash
+// matches at any depth in .gitignore, so it gets a leading **/.
+func AnchoredGlob(pattern string) string {
+	negate := strings.HasPrefix(pattern, "!")
+	pattern = strings.TrimPrefix(pattern, "!")
+	switch {
+	case strings.HasPrefix(pattern, "/"):
+		pattern = pattern[1:]
+	case !strings.Contains(strings.TrimSuffix(pattern, "/"), "/") && !strings.HasPrefix(pattern, "**"):
+		pattern = "**/" + pattern
+	}
+	if negate {
+		pattern = "!" + pattern
+	}
+	return pattern
+}
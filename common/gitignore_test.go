@@ -0,0 +1,148 @@
+package common
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseGitignoreBundle(t *testing.T) {
+	want := map[string]string{
+		"Go.gitignore":           "*.test\n*.out\n",
+		"Global/macOS.gitignore": ".DS_Store\n",
+	}
+	// Written the way scripts/get_all_gitignore_names.go writes the bundle
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(writer).Encode(want); err != nil {
+		t.Fatal(err)
+	}
+	writer.Close()
+
+	templates, err := ParseGitignoreBundle(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(templates, want) {
+		t.Errorf("ParseGitignoreBundle = %v, want %v", templates, want)
+	}
+
+	if _, err := ParseGitignoreBundle([]byte("{}")); err == nil {
+		t.Error("ParseGitignoreBundle accepted a bundle that is not gzipped")
+	}
+	// The embedded bundle must at least decode; CI checks it is not empty
+	if _, err := GitignoreTemplates(); err != nil {
+		t.Errorf("GitignoreTemplates: %v", err)
+	}
+}
+
+func TestSetBlockRoundTrip(t *testing.T) {
+	const node = "# Logs\nlogs\n*.log\nnode_modules/\n"
+	tests := []struct {
+		name, content, template, templateContent, want string
+	}{
+		{
+			name:            "empty file",
+			template:        "Node",
+			templateContent: node,
+			want:            "# >>> setup:Node\n# Logs\nlogs\n*.log\nnode_modules/\n# <<< setup:Node\n",
+		},
+		{
+			name:            "missing block",
+			content:         "node_modules/\n.env\n",
+			template:        "Node",
+			templateContent: node,
+			// Patterns written above the block are left out of it
+			want: "node_modules/\n.env\n\n# >>> setup:Node\n# Logs\nlogs\n*.log\n# <<< setup:Node\n",
+		},
+		{
+			name:            "existing block",
+			content:         ".env\n\n# >>> setup:Node\nlogs\n# <<< setup:Node\n\n# by hand\ncoverage/\n",
+			template:        "Node",
+			templateContent: node,
+			want:            ".env\n\n# >>> setup:Node\n# Logs\nlogs\n*.log\nnode_modules/\n# <<< setup:Node\n\n# by hand\ncoverage/\n",
+		},
+		{
+			name:            "second block",
+			content:         "# >>> setup:Node\nlogs\n# <<< setup:Node\n",
+			template:        "Go",
+			templateContent: "*.test\nlogs\r\n",
+			want:            "# >>> setup:Node\nlogs\n# <<< setup:Node\n\n# >>> setup:Go\n*.test\n# <<< setup:Go\n",
+		},
+		{
+			name:            "block without its end marker",
+			content:         "# >>> setup:Node\nlogs\n",
+			template:        "Node",
+			templateContent: "dist/\n",
+			want:            "# >>> setup:Node\nlogs\n\n# >>> setup:Node\ndist/\n# <<< setup:Node\n",
+		},
+		{
+			name:            "crlf",
+			content:         ".env\r\n# >>> setup:Node\r\nlogs\r\n# <<< setup:Node\r\n",
+			template:        "Node",
+			templateContent: "logs\r\ndist/\r\n",
+			want:            ".env\n# >>> setup:Node\nlogs\ndist/\n# <<< setup:Node\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := ParseGitignore(tt.content)
+			file.SetBlock(tt.template, tt.templateContent)
+			got := file.String()
+			if got != tt.want {
+				t.Fatalf("SetBlock = %q, want %q", got, tt.want)
+			}
+
+			// A second run over the written file changes nothing
+			again := ParseGitignore(got)
+			again.SetBlock(tt.template, tt.templateContent)
+			if again.String() != got {
+				t.Errorf("second SetBlock = %q, want %q", again.String(), got)
+			}
+		})
+	}
+}
+
+func TestParseGitignore(t *testing.T) {
+	file := ParseGitignore(".env\n# >>> setup:Node\nlogs\n# <<< setup:Node\n# >>> setup:Go\n\n# <<< setup:Go\ncoverage/\n")
+	want := []GitignorePart{
+		{Lines: []string{".env"}},
+		{Template: "Node", Lines: []string{"logs"}},
+		{Template: "Go", Lines: []string{""}},
+		{Lines: []string{"coverage/"}},
+	}
+	if !reflect.DeepEqual(file.Parts, want) {
+		t.Errorf("Parts = %q, want %q", file.Parts, want)
+	}
+	if applied := file.Applied(); !reflect.DeepEqual(applied, []string{"Node", "Go"}) {
+		t.Errorf("Applied = %v", applied)
+	}
+	if lines, ok := file.Block("Node"); !ok || !reflect.DeepEqual(lines, []string{"logs"}) {
+		t.Errorf("Block(Node) = %v, %v", lines, ok)
+	}
+	if _, ok := file.Block("Python"); ok {
+		t.Error("Block(Python) found a block that is not there")
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want []string
+	}{
+		{"same", []string{"logs", "dist/"}, []string{"logs", "dist/"}, nil},
+		{"added", []string{"logs"}, []string{"logs", "dist/"}, []string{"+dist/"}},
+		{"removed", []string{"logs", "dist/"}, []string{"dist/"}, []string{"-logs"}},
+		{"changed", []string{"logs", "*.log", "dist/"}, []string{"logs", "*.log.*", "dist/"}, []string{"-*.log", "+*.log.*"}},
+		{"from nothing", nil, []string{"logs"}, []string{"+logs"}},
+		{"to nothing", []string{"logs"}, nil, []string{"-logs"}},
+	}
+	for _, tt := range tests {
+		if got := DiffLines(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: DiffLines = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultGitignoreBaseURL serves the raw files of github/gitignore.
+const DefaultGitignoreBaseURL = "https://raw.githubusercontent.com/github/gitignore/main/"
+
+// GitignoreBaseURLEnv overrides the base URL, e.g. with a GitHub Enterprise or
+// file-server mirror of github/gitignore.
+const GitignoreBaseURLEnv = "SETUP_GITIGNORE_BASE_URL"
+
+// GitignoreBaseURL returns the flag value if set, then the environment
+// variable, then the default.
+func GitignoreBaseURL(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	if env := os.Getenv(GitignoreBaseURLEnv); env != "" {
+		return env
+	}
+	return DefaultGitignoreBaseURL
+}
+
+// GitignoreFetcher downloads templates from a github/gitignore mirror and
+// keeps them in a cache directory, revalidated with their ETag.
+type GitignoreFetcher struct {
+	BaseURL  string
+	Client   *http.Client
+	CacheDir string // empty disables the cache
+}
+
+// NewGitignoreFetcher returns a fetcher for baseURL caching in the user cache
+// directory, one subdirectory per mirror.
+func NewGitignoreFetcher(baseURL string) *GitignoreFetcher {
+	fetcher := &GitignoreFetcher{
+		BaseURL: baseURL,
+		Client:  &http.Client{Timeout: 30 * time.Second},
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		sum := sha256.Sum256([]byte(baseURL))
+		fetcher.CacheDir = filepath.Join(dir, "setup", "gitignore", hex.EncodeToString(sum[:6]))
+	}
+	return fetcher
+}
+
+// Cached returns the cached copy of a template.
+func (f *GitignoreFetcher) Cached(path string) ([]byte, bool) {
+	if f.CacheDir == "" || CheckTemplatePath(path) != nil {
+		return nil, false
+	}
+	content, err := os.ReadFile(f.cachePath(path))
+	return content, err == nil
+}
+
+// Fetch returns a template, sending the ETag of the cached copy so an
+// unchanged template is not downloaded again. With refresh the cache is
+// bypassed. If the mirror cannot be reached the cached copy is used.
+func (f *GitignoreFetcher) Fetch(path string, refresh bool) ([]byte, error) {
+	if err := CheckTemplatePath(path); err != nil {
+		return nil, err
+	}
+	url := strings.TrimSuffix(f.BaseURL, "/") + "/" + path
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, hasCache := f.Cached(path)
+	if hasCache && !refresh {
+		if etag, err := os.ReadFile(f.cachePath(path) + ".etag"); err == nil {
+			req.Header.Set("If-None-Match", strings.TrimSpace(string(etag)))
+		}
+	}
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		if hasCache && !refresh {
+			return cached, nil
+		}
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && hasCache:
+		return cached, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("fetching %s: %s", url, resp.Status)
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+
+	// A cache that cannot be written only costs a download next time
+	_ = f.store(path, content, resp.Header.Get("ETag"))
+	return content, nil
+}
+
+// CheckTemplatePath rejects template paths that could leave the mirror or
+// the cache directory: absolute paths and paths with a ".." segment.
+func CheckTemplatePath(p string) error {
+	slashed := strings.ReplaceAll(p, `\`, "/")
+	if p == "" || strings.HasPrefix(slashed, "/") || filepath.IsAbs(p) || filepath.VolumeName(p) != "" {
+		return fmt.Errorf("invalid template path %q: it must be relative", p)
+	}
+	for _, segment := range strings.Split(slashed, "/") {
+		if segment == ".." {
+			return fmt.Errorf("invalid template path %q: it must not contain ..", p)
+		}
+	}
+	return nil
+}
+
+func (f *GitignoreFetcher) cachePath(path string) string {
+	return filepath.Join(f.CacheDir, filepath.FromSlash(path))
+}
+
+func (f *GitignoreFetcher) store(path string, content []byte, etag string) error {
+	if f.CacheDir == "" {
+		return nil
+	}
+	file := f.cachePath(path)
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, content, 0644); err != nil {
+		return err
+	}
+	if etag == "" {
+		err := os.Remove(file + ".etag")
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	return os.WriteFile(file+".etag", []byte(etag), 0644)
+}
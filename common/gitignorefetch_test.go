@@ -0,0 +1,144 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// stubMirror serves Go.gitignore under /mirror/ with an ETag and counts the
+// full downloads and the requests revalidated with 304.
+type stubMirror struct {
+	downloads, notModified int
+}
+
+func (m *stubMirror) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/mirror/Go.gitignore" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Header.Get("If-None-Match") == `"v1"` {
+		m.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	m.downloads++
+	w.Header().Set("ETag", `"v1"`)
+	w.Write([]byte("*.exe\n"))
+}
+
+func newStubFetcher(t *testing.T) (*GitignoreFetcher, *stubMirror, *httptest.Server) {
+	t.Helper()
+	mirror := &stubMirror{}
+	server := httptest.NewServer(mirror)
+	t.Cleanup(server.Close)
+	return &GitignoreFetcher{BaseURL: server.URL + "/mirror/", Client: server.Client(), CacheDir: t.TempDir()}, mirror, server
+}
+
+func TestGitignoreFetcherWritesCache(t *testing.T) {
+	fetcher, mirror, _ := newStubFetcher(t)
+
+	content, err := fetcher.Fetch("Go.gitignore", false)
+	if err != nil || string(content) != "*.exe\n" {
+		t.Fatalf("Fetch = %q, %v", content, err)
+	}
+	if mirror.downloads != 1 {
+		t.Errorf("downloads = %d, want 1", mirror.downloads)
+	}
+	cached, err := os.ReadFile(filepath.Join(fetcher.CacheDir, "Go.gitignore"))
+	if err != nil || string(cached) != "*.exe\n" {
+		t.Errorf("cache = %q, %v", cached, err)
+	}
+	etag, err := os.ReadFile(filepath.Join(fetcher.CacheDir, "Go.gitignore.etag"))
+	if err != nil || string(etag) != `"v1"` {
+		t.Errorf("etag = %q, %v", etag, err)
+	}
+}
+
+func TestGitignoreFetcherRevalidates(t *testing.T) {
+	fetcher, mirror, _ := newStubFetcher(t)
+
+	for i := 0; i < 3; i++ {
+		content, err := fetcher.Fetch("Go.gitignore", false)
+		if err != nil || string(content) != "*.exe\n" {
+			t.Fatalf("Fetch #%d = %q, %v", i, content, err)
+		}
+	}
+	if mirror.downloads != 1 || mirror.notModified != 2 {
+		t.Errorf("downloads = %d, not modified = %d, want 1 and 2", mirror.downloads, mirror.notModified)
+	}
+
+	// --refresh downloads again without sending the ETag
+	if _, err := fetcher.Fetch("Go.gitignore", true); err != nil {
+		t.Fatal(err)
+	}
+	if mirror.downloads != 2 {
+		t.Errorf("downloads after refresh = %d, want 2", mirror.downloads)
+	}
+}
+
+func TestGitignoreFetcherErrors(t *testing.T) {
+	fetcher, _, server := newStubFetcher(t)
+
+	if _, err := fetcher.Fetch("Missing.gitignore", false); err == nil {
+		t.Error("Fetch of a missing template succeeded")
+	}
+
+	// An unreachable mirror falls back to the cached copy
+	if _, err := fetcher.Fetch("Go.gitignore", false); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+	content, err := fetcher.Fetch("Go.gitignore", false)
+	if err != nil || string(content) != "*.exe\n" {
+		t.Errorf("Fetch with the mirror down = %q, %v", content, err)
+	}
+	if _, err := fetcher.Fetch("Go.gitignore", true); err == nil {
+		t.Error("refresh with the mirror down succeeded")
+	}
+}
+
+func TestGitignoreFetcherOfflineCacheHit(t *testing.T) {
+	fetcher, _, _ := newStubFetcher(t)
+
+	if _, ok := fetcher.Cached("Go.gitignore"); ok {
+		t.Fatal("Cached before any fetch")
+	}
+	if _, err := fetcher.Fetch("Go.gitignore", false); err != nil {
+		t.Fatal(err)
+	}
+	content, ok := fetcher.Cached("Go.gitignore")
+	if !ok || string(content) != "*.exe\n" {
+		t.Errorf("Cached = %q, %v", content, ok)
+	}
+}
+
+func TestGitignoreBaseURL(t *testing.T) {
+	tests := []struct {
+		name, flag, env, want string
+	}{
+		{"default", "", "", DefaultGitignoreBaseURL},
+		{"environment", "", "https://ghe.example.com/raw/", "https://ghe.example.com/raw/"},
+		{"flag wins", "http://files.example.com/gitignore", "https://ghe.example.com/raw/", "http://files.example.com/gitignore"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(GitignoreBaseURLEnv, tt.env)
+			if got := GitignoreBaseURL(tt.flag); got != tt.want {
+				t.Errorf("GitignoreBaseURL(%q) = %q, want %q", tt.flag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewGitignoreFetcherCachePerMirror(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	a, b := NewGitignoreFetcher("https://a.example.com/"), NewGitignoreFetcher("https://b.example.com/")
+	if a.CacheDir == "" || a.CacheDir == b.CacheDir {
+		t.Errorf("cache dirs %q and %q, want distinct per mirror", a.CacheDir, b.CacheDir)
+	}
+}
@@ -0,0 +1,147 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultGitHubAPIURL is the REST API of github.com. GitHub Enterprise and
+// GitHub Actions provide theirs in $GITHUB_API_URL.
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// GitignoreIndex lists the templates of a github/gitignore repository through
+// the git trees API of GitHub.
+type GitignoreIndex struct {
+	APIURL string
+	Repo   string // owner/name
+	Ref    string
+	Token  string // sent when set, raising the rate limit of 60 requests an hour
+	Client *http.Client
+}
+
+// NewGitignoreIndex returns an index of github/gitignore on main, using
+// $GITHUB_API_URL and $GITHUB_TOKEN when set.
+func NewGitignoreIndex() *GitignoreIndex {
+	apiURL := os.Getenv("GITHUB_API_URL")
+	if apiURL == "" {
+		apiURL = DefaultGitHubAPIURL
+	}
+	return &GitignoreIndex{
+		APIURL: apiURL,
+		Repo:   "github/gitignore",
+		Ref:    "main",
+		Token:  os.Getenv("GITHUB_TOKEN"),
+		Client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// gitTree is a response of the git trees API.
+type gitTree struct {
+	Tree []struct {
+		Path string `json:"path"`
+		Type string `json:"type"`
+		SHA  string `json:"sha"`
+	} `json:"tree"`
+	Truncated bool `json:"truncated"`
+}
+
+// Fetch returns the template keys and their paths, e.g. "Global/macOS" and
+// "Global/macOS.gitignore". When the recursive listing is truncated, the
+// trees are listed again one directory at a time.
+func (x *GitignoreIndex) Fetch() (map[string]string, error) {
+	tree, err := x.tree(x.Ref, true)
+	if err != nil {
+		return nil, err
+	}
+	pairs := make(map[string]string)
+	if tree.Truncated {
+		return pairs, x.walk(x.Ref, "", pairs)
+	}
+	for _, entry := range tree.Tree {
+		addGitignorePair(pairs, entry.Path, entry.Type)
+	}
+	return pairs, nil
+}
+
+func (x *GitignoreIndex) walk(sha, dir string, pairs map[string]string) error {
+	tree, err := x.tree(sha, false)
+	if err != nil {
+		return err
+	}
+	if tree.Truncated {
+		return fmt.Errorf("listing %s: the tree of %s/ is truncated", x.Repo, dir)
+	}
+	for _, entry := range tree.Tree {
+		file := path.Join(dir, entry.Path)
+		if entry.Type == "tree" {
+			if err := x.walk(entry.SHA, file, pairs); err != nil {
+				return err
+			}
+			continue
+		}
+		addGitignorePair(pairs, file, entry.Type)
+	}
+	return nil
+}
+
+func addGitignorePair(pairs map[string]string, file, kind string) {
+	if kind == "blob" && strings.HasSuffix(file, ".gitignore") && path.Base(file) != ".gitignore" {
+		pairs[strings.TrimSuffix(file, ".gitignore")] = file
+	}
+}
+
+// tree requests a tree by its SHA or a ref.
+func (x *GitignoreIndex) tree(sha string, recursive bool) (*gitTree, error) {
+	url := fmt.Sprintf("%s/repos/%s/git/trees/%s", strings.TrimSuffix(x.APIURL, "/"), x.Repo, sha)
+	if recursive {
+		url += "?recursive=1"
+	}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if x.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+x.Token)
+	}
+
+	resp, err := x.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("listing %s: %w", x.Repo, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		err := fmt.Errorf("listing %s: %s %s", x.Repo, resp.Status, body.Message)
+		if x.Token == "" && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests) {
+			err = fmt.Errorf("%w (set GITHUB_TOKEN to raise the rate limit)", err)
+		}
+		return nil, err
+	}
+
+	var tree gitTree
+	if err := json.NewDecoder(resp.Body).Decode(&tree); err != nil {
+		return nil, fmt.Errorf("listing %s: %w", x.Repo, err)
+	}
+	return &tree, nil
+}
+
+// GitignoreIndexCache returns where setup ignore sync keeps the index, which
+// then replaces the embedded one.
+func GitignoreIndexCache() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "setup", "gitignore_pairs.json"), nil
+}
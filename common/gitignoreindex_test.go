@@ -0,0 +1,109 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stubTreesAPI serves the git trees API of github/gitignore from canned
+// responses keyed by path and query.
+func stubTreesAPI(t *testing.T, responses map[string]string, token string) *GitignoreIndex {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"API rate limit exceeded"}`))
+			return
+		}
+		body, ok := responses[r.URL.Path+"?"+r.URL.RawQuery]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Not Found"}`))
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return &GitignoreIndex{APIURL: server.URL, Repo: "github/gitignore", Ref: "main", Client: server.Client()}
+}
+
+const treesPath = "/repos/github/gitignore/git/trees/"
+
+func TestGitignoreIndexFetch(t *testing.T) {
+	index := stubTreesAPI(t, map[string]string{
+		treesPath + "main?recursive=1": `{"tree":[
+			{"path":"Go.gitignore","type":"blob"},
+			{"path":"Global","type":"tree","sha":"g1"},
+			{"path":"Global/macOS.gitignore","type":"blob"},
+			{"path":"README.md","type":"blob"},
+			{"path":".github/.gitignore","type":"blob"}
+		],"truncated":false}`,
+	}, "")
+
+	pairs, err := index.Fetch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"Go": "Go.gitignore", "Global/macOS": "Global/macOS.gitignore"}
+	if !reflect.DeepEqual(pairs, want) {
+		t.Errorf("Fetch = %v, want %v", pairs, want)
+	}
+}
+
+func TestGitignoreIndexFetchTruncated(t *testing.T) {
+	index := stubTreesAPI(t, map[string]string{
+		treesPath + "main?recursive=1": `{"tree":[{"path":"Go.gitignore","type":"blob"}],"truncated":true}`,
+		treesPath + "main?":            `{"tree":[{"path":"Go.gitignore","type":"blob"},{"path":"community","type":"tree","sha":"c1"}]}`,
+		treesPath + "c1?":              `{"tree":[{"path":"Golang","type":"tree","sha":"c2"}]}`,
+		treesPath + "c2?":              `{"tree":[{"path":"Hugo.gitignore","type":"blob"}]}`,
+	}, "secret")
+	index.Token = "secret"
+
+	pairs, err := index.Fetch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"Go": "Go.gitignore", "community/Golang/Hugo": "community/Golang/Hugo.gitignore"}
+	if !reflect.DeepEqual(pairs, want) {
+		t.Errorf("Fetch = %v, want %v", pairs, want)
+	}
+}
+
+func TestGitignoreIndexFetchErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses map[string]string
+		token     string
+		want      string
+	}{
+		{"rate limited", nil, "secret", "set GITHUB_TOKEN"},
+		{"not found", map[string]string{}, "", "404"},
+		{"bad json", map[string]string{treesPath + "main?recursive=1": `{"tree":`}, "", "listing github/gitignore"},
+		{"truncated subtree", map[string]string{
+			treesPath + "main?recursive=1": `{"truncated":true}`,
+			treesPath + "main?":            `{"truncated":true}`,
+		}, "", "truncated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the stub expects tt.token, the index sends none
+			_, err := stubTreesAPI(t, tt.responses, tt.token).Fetch()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Fetch error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewGitignoreIndexEnvironment(t *testing.T) {
+	t.Setenv("GITHUB_API_URL", "https://ghe.example.com/api/v3")
+	t.Setenv("GITHUB_TOKEN", "secret")
+
+	index := NewGitignoreIndex()
+	if index.APIURL != "https://ghe.example.com/api/v3" || index.Token != "secret" {
+		t.Errorf("NewGitignoreIndex = %+v", index)
+	}
+}
@@ -0,0 +1,252 @@
+package common
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// GitignoreRule is a pattern of a .gitignore file.
+type GitignoreRule struct {
+	File    string // the .gitignore, relative to the root
+	Line    int
+	Pattern string // as written
+	Negate  bool
+	DirOnly bool
+
+	base     string // directory of File, empty for the root
+	basename bool   // without a slash the pattern matches the name at any depth
+	glob     string // the pattern without !, leading and trailing slashes
+	re       *regexp.Regexp
+}
+
+// ParseGitignoreRules parses the patterns of a .gitignore at file, a path
+// relative to the root, following gitignore(5).
+func ParseGitignoreRules(file, content string) []GitignoreRule {
+	base := path.Dir(filepath.ToSlash(file))
+	if base == "." {
+		base = ""
+	}
+
+	var rules []GitignoreRule
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		// Trailing spaces are ignored unless escaped
+		for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+			line = line[:len(line)-1]
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := GitignoreRule{File: file, Line: i + 1, Pattern: line, base: base}
+		pattern := line
+		if strings.HasPrefix(pattern, "!") {
+			rule.Negate = true
+			pattern = pattern[1:]
+		}
+		if strings.HasSuffix(pattern, "/") {
+			rule.DirOnly = true
+			pattern = strings.TrimSuffix(pattern, "/")
+		}
+		rule.basename = !strings.Contains(pattern, "/")
+		pattern = strings.TrimPrefix(pattern, "/")
+		if pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile("^" + globToRegexp(pattern) + "$")
+		if err != nil {
+			continue
+		}
+		rule.re = re
+		rule.glob = pattern
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// globToRegexp translates a gitignore glob: * and ? do not cross slashes,
+// a leading **/ matches in all directories, /**/ matches zero or more
+// directories and a trailing /** everything inside.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if strings.HasPrefix(glob[i:], "**") &&
+				(i == 0 || glob[i-1] == '/') && (i+2 == len(glob) || glob[i+2] == '/') {
+				if i+2 == len(glob) {
+					b.WriteString(".*")
+				} else {
+					b.WriteString("(?:.*/)?")
+				}
+				i += 2
+				continue
+			}
+			for i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+			}
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[min(i+2, len(glob)):], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			end += min(i+2, len(glob))
+			class := glob[i+1 : end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i = end
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// Match reports whether the rule matches a path relative to the root.
+func (r *GitignoreRule) Match(p string, isDir bool) bool {
+	if r.DirOnly && !isDir {
+		return false
+	}
+	if r.base != "" {
+		rest, ok := strings.CutPrefix(p, r.base+"/")
+		if !ok {
+			return false
+		}
+		p = rest
+	}
+	if r.basename {
+		p = path.Base(p)
+	}
+	return r.re.MatchString(p)
+}
+
+// LiteralDir returns the directory an anchored pattern is confined to, made of
+// its leading segments without wildcards, relative to the root. It is empty
+// when the pattern can match directly under its .gitignore.
+func (r *GitignoreRule) LiteralDir() string {
+	if r.basename {
+		return ""
+	}
+	segments := strings.Split(r.glob, "/")
+	var dir []string
+	for _, segment := range segments[:len(segments)-1] {
+		if strings.ContainsAny(segment, `*?[\`) {
+			break
+		}
+		dir = append(dir, segment)
+	}
+	if len(dir) == 0 {
+		return ""
+	}
+	return path.Join(r.base, path.Join(dir...))
+}
+
+// GitignoreMatcher holds the rules of the .gitignore files of a tree, those
+// of parent directories before those of their subdirectories.
+type GitignoreMatcher struct {
+	Rules []GitignoreRule
+}
+
+// LoadGitignoreMatcher reads the .gitignore files under root. As with git,
+// those inside ignored directories are not read.
+func LoadGitignoreMatcher(root string) (*GitignoreMatcher, error) {
+	m := &GitignoreMatcher{}
+	return m, m.Walk(root, func(string, bool) {})
+}
+
+// Walk visits the paths under root, relative to it, reading the .gitignore of
+// every directory before its contents. Ignored paths are visited too, but not
+// the contents of ignored directories nor .git.
+func (m *GitignoreMatcher) Walk(root string, visit func(p string, isDir bool)) error {
+	return filepath.WalkDir(root, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !entry.IsDir() {
+			visit(rel, false)
+			return nil
+		}
+
+		gitignore := ".gitignore"
+		if rel != "." {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			visit(rel, true)
+			if _, ignored := m.Match(rel, true); ignored {
+				return filepath.SkipDir
+			}
+			gitignore = rel + "/.gitignore"
+		}
+		data, err := os.ReadFile(filepath.Join(file, ".gitignore"))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		m.Rules = append(m.Rules, ParseGitignoreRules(gitignore, string(data))...)
+		return nil
+	})
+}
+
+// Match reports whether a path relative to the root is ignored, with the
+// rule deciding it, nil when no rule matches.
+func (m *GitignoreMatcher) Match(p string, isDir bool) (*GitignoreRule, bool) {
+	return m.MatchBefore(p, isDir, len(m.Rules))
+}
+
+// MatchBefore decides a path as Match does, with only the first n rules for
+// the path itself. Inside an ignored directory, which git does not look
+// into, the rule ignoring the directory decides.
+func (m *GitignoreMatcher) MatchBefore(p string, isDir bool, n int) (*GitignoreRule, bool) {
+	p = path.Clean(filepath.ToSlash(p))
+	if rule := m.IgnoredParent(p); rule != nil {
+		return rule, true
+	}
+	return m.last(p, isDir, n)
+}
+
+// IgnoredParent returns the rule ignoring a parent directory of the path, nil
+// when none is ignored.
+func (m *GitignoreMatcher) IgnoredParent(p string) *GitignoreRule {
+	for i := 0; i < len(p); i++ {
+		if p[i] != '/' {
+			continue
+		}
+		if rule, ignored := m.last(p[:i], true, len(m.Rules)); ignored {
+			return rule
+		}
+	}
+	return nil
+}
+
+// last returns the last of the first n rules matching the path.
+func (m *GitignoreMatcher) last(p string, isDir bool, n int) (*GitignoreRule, bool) {
+	for i := n - 1; i >= 0; i-- {
+		if m.Rules[i].Match(p, isDir) {
+			return &m.Rules[i], !m.Rules[i].Negate
+		}
+	}
+	return nil, false
+}
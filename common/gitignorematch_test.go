@@ -0,0 +1,210 @@
+package common
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		glob, want string
+	}{
+		{"*.log", `[^/]*\.log`},
+		{"a**b", `a[^/]*b`},
+		{"secret?.txt", `secret[^/]\.txt`},
+		{"**/cache", `(?:.*/)?cache`},
+		{"logs/**", `logs/.*`},
+		{"docs/**/*.tmp", `docs/(?:.*/)?[^/]*\.tmp`},
+		{"data[0-9].csv", `data[0-9]\.csv`},
+		{"[!a]b", `[^a]b`},
+		{"[]]x", `[]]x`},
+		{"[oops", `\[oops`},
+		{`\#hash`, `#hash`},
+		{`\*star`, `\*star`},
+	}
+	for _, tt := range tests {
+		if got := globToRegexp(tt.glob); got != tt.want {
+			t.Errorf("globToRegexp(%q) = %q, want %q", tt.glob, got, tt.want)
+		}
+	}
+}
+
+// matchTree is laid out so that every case of TestGitignoreMatcher exists on
+// disk, which git check-ignore needs to tell files from directories.
+var matchTree = map[string]string{
+	".gitignore": `*.log
+!keep.log
+build/
+/dist
+docs/**/*.tmp
+**/cache
+logs/**
+!logs/important.txt
+secret?.txt
+data[0-9].csv
+\#hash
+vendor/
+!vendor/keep
+`,
+	"sub/.gitignore": `local.txt
+/anchored.txt
+!debug.log
+`,
+	// Not read: git does not look into ignored directories
+	"vendor/.gitignore": "!keep\n",
+}
+
+var matchCases = []struct {
+	path    string
+	isDir   bool
+	ignored bool
+}{
+	{"app.log", false, true},
+	{"keep.log", false, false},
+	{"sub/app.log", false, true},
+	{"sub/debug.log", false, false},
+	{"build", true, true},
+	{"build/out.js", false, true},
+	{"src/build", false, false},
+	{"dist", true, true},
+	{"sub/dist", true, false},
+	{"a.tmp", false, false},
+	{"docs/a.tmp", false, true},
+	{"docs/x/y/a.tmp", false, true},
+	{"sub/deep/cache", true, true},
+	{"sub/deep/cache/entry", false, true},
+	{"logs/important.txt", false, false},
+	{"logs/other.txt", false, true},
+	{"secret1.txt", false, true},
+	{"secret10.txt", false, false},
+	{"data5.csv", false, true},
+	{"datax.csv", false, false},
+	{"#hash", false, true},
+	{"vendor/keep", false, true},
+	{"sub/local.txt", false, true},
+	{"sub/x/local.txt", false, true},
+	{"sub/anchored.txt", false, true},
+	{"sub/x/anchored.txt", false, false},
+	{"anchored.txt", false, false},
+}
+
+func writeMatchTree(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	write := func(file, content string) {
+		file = filepath.Join(root, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for file, content := range matchTree {
+		write(file, content)
+	}
+	for _, c := range matchCases {
+		if c.isDir {
+			os.MkdirAll(filepath.Join(root, filepath.FromSlash(c.path)), 0755)
+		} else {
+			write(c.path, "")
+		}
+	}
+	return root
+}
+
+func TestGitignoreMatcher(t *testing.T) {
+	m, err := LoadGitignoreMatcher(writeMatchTree(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rule := range m.Rules {
+		if rule.File == "vendor/.gitignore" {
+			t.Errorf("read %s inside an ignored directory", rule.File)
+		}
+	}
+	for _, c := range matchCases {
+		if _, ignored := m.Match(c.path, c.isDir); ignored != c.ignored {
+			t.Errorf("Match(%q) = %v, want %v", c.path, ignored, c.ignored)
+		}
+	}
+}
+
+// TestGitignoreMatcherAgreesWithGit checks the expected results of
+// TestGitignoreMatcher against git check-ignore.
+func TestGitignoreMatcherAgreesWithGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	root := writeMatchTree(t)
+	if out, err := exec.Command("git", "-C", root, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	for _, c := range matchCases {
+		err := exec.Command("git", "-C", root, "check-ignore", "-q", c.path).Run()
+		var exit *exec.ExitError
+		if err != nil && (!errors.As(err, &exit) || exit.ExitCode() != 1) {
+			t.Fatalf("git check-ignore %s: %v", c.path, err)
+		}
+		if ignored := err == nil; ignored != c.ignored {
+			t.Errorf("git check-ignore %q = %v, the case says %v", c.path, ignored, c.ignored)
+		}
+	}
+}
+
+func TestGitignoreMatcherDecidingRule(t *testing.T) {
+	m, err := LoadGitignoreMatcher(writeMatchTree(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := func(pattern string) int {
+		for i, rule := range m.Rules {
+			if rule.Pattern == pattern {
+				return i
+			}
+		}
+		t.Fatalf("no rule %q", pattern)
+		return -1
+	}
+
+	// Without the negation after it, *.log decides keep.log
+	rule, ignored := m.MatchBefore("keep.log", false, index("!keep.log"))
+	if !ignored || rule.Pattern != "*.log" {
+		t.Errorf("MatchBefore(keep.log) = %v, %v, want *.log", rule, ignored)
+	}
+	if rule, ignored := m.Match("keep.log", false); ignored || rule.Pattern != "!keep.log" || rule.Line != 2 {
+		t.Errorf("Match(keep.log) = %+v, %v, want !keep.log", rule, ignored)
+	}
+
+	// The rule ignoring a parent decides whatever follows
+	for p, want := range map[string]string{
+		"build/out.js":         "build/",
+		"vendor/keep":          "vendor/",
+		"sub/deep/cache/entry": "**/cache",
+		"logs/important.txt":   "",
+		"sub/x/local.txt":      "",
+	} {
+		got := ""
+		if rule := m.IgnoredParent(p); rule != nil {
+			got = rule.Pattern
+		}
+		if got != want {
+			t.Errorf("IgnoredParent(%q) = %q, want %q", p, got, want)
+		}
+	}
+	if rule, _ := m.MatchBefore("vendor/keep", false, len(m.Rules)); rule == nil || rule.Pattern != "vendor/" {
+		t.Errorf("MatchBefore(vendor/keep) = %+v, want vendor/", rule)
+	}
+
+	// Rules of a nested .gitignore are relative to its directory
+	if rule, _ := m.Match("sub/anchored.txt", false); rule == nil || rule.File != "sub/.gitignore" {
+		t.Errorf("Match(sub/anchored.txt) = %+v, want the rule of sub/.gitignore", rule)
+	}
+	if got := strings.Join([]string{m.Rules[index("docs/**/*.tmp")].LiteralDir(), m.Rules[index("/anchored.txt")].LiteralDir()}, ","); got != "docs," {
+		t.Errorf("LiteralDir = %q, want %q", got, "docs,")
+	}
+}
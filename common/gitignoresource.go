@@ -0,0 +1,202 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// GitignoreSourcesEnv lists extra template sources, separated by commas, in
+// the form of the --source flag.
+const GitignoreSourcesEnv = "SETUP_GITIGNORE_SOURCES"
+
+// Kinds of GitignoreSource.
+const (
+	SourceDir  = "dir"  // a local directory of *.gitignore files
+	SourceGit  = "git"  // a git repository, cloned into the user cache
+	SourceHTTP = "http" // a JSON index of template keys and paths, like gitignore_pairs.json
+)
+
+// GitignoreSource is a collection of templates besides github/gitignore. Its
+// templates are listed as Name/<template>, e.g. acme/Service.
+type GitignoreSource struct {
+	Name     string
+	Kind     string
+	Location string
+	Offline  bool // use the cached clone or index only
+}
+
+// ParseGitignoreSource parses name=location. git+<url> and locations ending in
+// .git are git repositories, other http:// and https:// locations are indexes,
+// and anything else is a local directory.
+func ParseGitignoreSource(spec string) (GitignoreSource, error) {
+	name, location, ok := strings.Cut(spec, "=")
+	name, location = strings.TrimSpace(name), strings.TrimSpace(location)
+	if !ok || name == "" || location == "" || strings.Contains(name, "/") {
+		return GitignoreSource{}, fmt.Errorf("invalid template source %q, want name=location", spec)
+	}
+	if name == "Global" || name == "community" {
+		return GitignoreSource{}, fmt.Errorf("template source %q clashes with the directories of github/gitignore", name)
+	}
+
+	source := GitignoreSource{Name: name, Kind: SourceDir, Location: location}
+	switch {
+	case strings.HasPrefix(location, "git+"):
+		source.Kind, source.Location = SourceGit, strings.TrimPrefix(location, "git+")
+	case strings.HasSuffix(location, ".git"):
+		source.Kind = SourceGit
+	case strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://"):
+		source.Kind = SourceHTTP
+	}
+	return source, nil
+}
+
+// Templates returns the templates of the source, keyed without the source
+// prefix, with the path Content expects.
+func (s GitignoreSource) Templates() (map[string]string, error) {
+	switch s.Kind {
+	case SourceHTTP:
+		fetcher, index := s.indexFetcher()
+		var data []byte
+		var err error
+		if cached, ok := fetcher.Cached(index); ok && s.Offline {
+			data = cached
+		} else if s.Offline {
+			return nil, fmt.Errorf("template source %s: the index is not cached", s.Name)
+		} else if data, err = fetcher.Fetch(index, false); err != nil {
+			return nil, fmt.Errorf("template source %s: %w", s.Name, err)
+		}
+		var templates map[string]string
+		if err := json.Unmarshal(data, &templates); err != nil {
+			return nil, fmt.Errorf("template source %s: reading the index: %w", s.Name, err)
+		}
+		for key, file := range templates {
+			if err := CheckTemplatePath(file); err != nil {
+				return nil, fmt.Errorf("template source %s: index entry %s: %w", s.Name, key, err)
+			}
+		}
+		return templates, nil
+	default:
+		root, err := s.root()
+		if err != nil {
+			return nil, err
+		}
+		templates := make(map[string]string)
+		err = filepath.WalkDir(root, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() && entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".gitignore") || entry.Name() == ".gitignore" {
+				return nil
+			}
+			rel, err := filepath.Rel(root, file)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+			templates[strings.TrimSuffix(rel, ".gitignore")] = rel
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("template source %s: %w", s.Name, err)
+		}
+		return templates, nil
+	}
+}
+
+// Content returns a template of the source by the path Templates listed.
+func (s GitignoreSource) Content(file string, refresh bool) ([]byte, error) {
+	if err := CheckTemplatePath(file); err != nil {
+		return nil, fmt.Errorf("template source %s: %w", s.Name, err)
+	}
+	if s.Kind == SourceHTTP {
+		fetcher, index := s.indexFetcher()
+		file = path.Join(path.Dir(index), file)
+		if cached, ok := fetcher.Cached(file); ok && s.Offline {
+			return cached, nil
+		} else if s.Offline {
+			return nil, fmt.Errorf("template source %s: %s is not cached", s.Name, file)
+		}
+		return fetcher.Fetch(file, refresh)
+	}
+	root, err := s.root()
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Join(root, filepath.FromSlash(file)))
+}
+
+// indexFetcher returns a fetcher for the host of the index and the path of
+// the index on it, so the templates are cached next to it.
+func (s GitignoreSource) indexFetcher() (*GitignoreFetcher, string) {
+	u, err := url.Parse(s.Location)
+	if err != nil {
+		return NewGitignoreFetcher(s.Location), ""
+	}
+	index := strings.TrimPrefix(u.Path, "/")
+	u.Path, u.RawQuery, u.Fragment = "/", "", ""
+	return NewGitignoreFetcher(u.String()), index
+}
+
+// Update pulls the clone of a git source made before, so a command sees the
+// latest templates; a source not cloned yet is cloned on first use. A failed
+// pull leaves the previous clone in place. Other kinds have nothing to update.
+func (s GitignoreSource) Update() error {
+	if s.Kind != SourceGit || s.Offline {
+		return nil
+	}
+	dir, err := s.cloneDir()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		return nil
+	}
+	if out, err := exec.Command("git", "-C", dir, "pull", "--quiet", "--ff-only").CombinedOutput(); err != nil {
+		return fmt.Errorf("template source %s: pulling %s: %v: %s", s.Name, s.Location, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// cloneDir returns where a git source is cloned in the user cache.
+func (s GitignoreSource) cloneDir() (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("template source %s: %w", s.Name, err)
+	}
+	sum := sha256.Sum256([]byte(s.Location))
+	return filepath.Join(cache, "setup", "sources", hex.EncodeToString(sum[:6])), nil
+}
+
+// root returns the directory holding the templates, cloning a git source into
+// the user cache the first time.
+func (s GitignoreSource) root() (string, error) {
+	if s.Kind != SourceGit {
+		return s.Location, nil
+	}
+	dir, err := s.cloneDir()
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		return dir, nil
+	}
+	if s.Offline {
+		return "", fmt.Errorf("template source %s: %s has not been cloned yet", s.Name, s.Location)
+	}
+	if out, err := exec.Command("git", "clone", "--quiet", "--depth", "1", s.Location, dir).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("template source %s: cloning %s: %v: %s", s.Name, s.Location, err, strings.TrimSpace(string(out)))
+	}
+	return dir, nil
+}
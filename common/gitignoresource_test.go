@@ -0,0 +1,147 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckTemplatePath(t *testing.T) {
+	tests := []struct {
+		path string
+		ok   bool
+	}{
+		{"Go.gitignore", true},
+		{"Global/macOS.gitignore", true},
+		{"tpl/..hidden.gitignore", true},
+		{"", false},
+		{"/etc/passwd", false},
+		{"../../.bashrc", false},
+		{"tpl/../../x.gitignore", false},
+		{`..\..\.bashrc`, false},
+	}
+	for _, tt := range tests {
+		if err := CheckTemplatePath(tt.path); (err == nil) != tt.ok {
+			t.Errorf("CheckTemplatePath(%q) = %v, want ok %v", tt.path, err, tt.ok)
+		}
+	}
+}
+
+func TestGitignoreSourceRejectsEscapingIndex(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/index.json" {
+			w.Write([]byte(`{"Service":"../../.bashrc"}`))
+			return
+		}
+		requested = true
+		w.Write([]byte("evil\n"))
+	}))
+	defer server.Close()
+
+	source, err := ParseGitignoreSource("acme=" + server.URL + "/index.json")
+	if err != nil || source.Kind != SourceHTTP {
+		t.Fatalf("ParseGitignoreSource = %+v, %v", source, err)
+	}
+	if _, err := source.Templates(); err == nil || !strings.Contains(err.Error(), "Service") {
+		t.Errorf("Templates error = %v, want the entry rejected", err)
+	}
+	if _, err := source.Content("../../.bashrc", false); err == nil {
+		t.Error("Content fetched a path escaping the cache")
+	}
+	if requested {
+		t.Error("the escaping path was requested from the server")
+	}
+}
+
+func TestGitignoreSourceDirectory(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "sub"), 0755)
+	os.WriteFile(filepath.Join(dir, "Service.gitignore"), []byte("secrets.env\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "sub", "Tools.gitignore"), []byte("out/\n"), 0644)
+	os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("*.tmp\n"), 0644)
+
+	source, err := ParseGitignoreSource("acme=" + dir)
+	if err != nil || source.Kind != SourceDir {
+		t.Fatalf("ParseGitignoreSource = %+v, %v", source, err)
+	}
+	templates, err := source.Templates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 2 || templates["sub/Tools"] != "sub/Tools.gitignore" {
+		t.Errorf("Templates = %v", templates)
+	}
+	content, err := source.Content(templates["Service"], false)
+	if err != nil || string(content) != "secrets.env\n" {
+		t.Errorf("Content = %q, %v", content, err)
+	}
+}
+
+func TestGitignoreSourceGitUpdate(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	repo := t.TempDir()
+	os.WriteFile(filepath.Join(repo, "Service.gitignore"), []byte("secrets.env\n"), 0644)
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=setup", "-c", "user.email=setup@example.com", "commit", "-qm", "templates"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	source, err := ParseGitignoreSource("acme=git+" + repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := source.Update(); err != nil {
+		t.Errorf("Update before the first clone = %v", err)
+	}
+	if templates, err := source.Templates(); err != nil || templates["Service"] != "Service.gitignore" {
+		t.Fatalf("Templates = %v, %v", templates, err)
+	}
+	if err := source.Update(); err != nil {
+		t.Errorf("Update = %v", err)
+	}
+
+	// The upstream is gone: the pull fails, the clone made before still serves
+	os.RemoveAll(repo)
+	if err := source.Update(); err == nil {
+		t.Error("Update succeeded without the upstream")
+	}
+	if templates, err := source.Templates(); err != nil || len(templates) != 1 {
+		t.Errorf("Templates after a failed pull = %v, %v", templates, err)
+	}
+}
+
+func TestParseGitignoreSource(t *testing.T) {
+	tests := []struct {
+		spec, kind, location string
+		ok                   bool
+	}{
+		{"acme=./templates", SourceDir, "./templates", true},
+		{"acme=git+https://git.example.com/acme/templates", SourceGit, "https://git.example.com/acme/templates", true},
+		{"acme=git@git.example.com:acme/templates.git", SourceGit, "git@git.example.com:acme/templates.git", true},
+		{"acme=https://files.example.com/gitignore/index.json", SourceHTTP, "https://files.example.com/gitignore/index.json", true},
+		{"acme", "", "", false},
+		{"a/b=./x", "", "", false},
+		{"Global=./x", "", "", false},
+	}
+	for _, tt := range tests {
+		source, err := ParseGitignoreSource(tt.spec)
+		if (err == nil) != tt.ok || (tt.ok && (source.Kind != tt.kind || source.Location != tt.location)) {
+			t.Errorf("ParseGitignoreSource(%q) = %+v, %v", tt.spec, source, err)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package common
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// LockedVersion returns the version of a package resolved in the project
+// lockfile and the lockfile it was read from. pnpm-lock.yaml, package-lock.json,
+// yarn.lock (classic and berry) and the text bun.lock are supported; the binary
+// bun.lockb is not.
+func LockedVersion(name string) (version string, lockfile string) {
+	parsers := []struct {
+		file  string
+		parse func(content, name string) string
+	}{
+		{"pnpm-lock.yaml", pnpmLockedVersion},
+		{"package-lock.json", npmLockedVersion},
+		{"yarn.lock", yarnLockedVersion},
+		{"bun.lock", bunLockedVersion},
+	}
+	for _, p := range parsers {
+		data, err := os.ReadFile(p.file)
+		if err != nil {
+			continue
+		}
+		if version := p.parse(string(data), name); version != "" {
+			return version, p.file
+		}
+	}
+	return "", ""
+}
+
+// pnpmLockedVersion reads the dependencies of the root project from
+// pnpm-lock.yaml: the top-level dependencies sections, or those of
+// importers['.'] in a workspace and in lockfile v9. Lockfile v5 writes
+// "name: 1.2.3" and lists the ranges apart under specifiers, v6 and later
+// write the range and the version under the name.
+func pnpmLockedVersion(content, name string) string {
+	var parents []yamlKey
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		for len(parents) > 0 && parents[len(parents)-1].indent >= indent {
+			parents = parents[:len(parents)-1]
+		}
+		k, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		k = strings.Trim(k, `'"`)
+		if k != name || !pnpmRootDependencies(parents) {
+			parents = append(parents, yamlKey{indent, k})
+			continue
+		}
+
+		if value = strings.TrimSpace(value); value != "" {
+			return cleanPnpmVersion(value)
+		}
+		for _, next := range lines[i+1:] {
+			nextTrimmed := strings.TrimSpace(next)
+			if nextTrimmed == "" {
+				continue
+			}
+			if len(next)-len(strings.TrimLeft(next, " ")) <= indent {
+				break
+			}
+			if v, ok := strings.CutPrefix(nextTrimmed, "version:"); ok {
+				return cleanPnpmVersion(strings.TrimSpace(v))
+			}
+		}
+		return ""
+	}
+	return ""
+}
+
+// yamlKey is a key of a YAML mapping enclosing a line, with its indentation.
+type yamlKey struct {
+	indent int
+	name   string
+}
+
+// pnpmRootDependencies reports whether the keys enclosing a line are a
+// dependencies section of the root project; specifiers never is.
+func pnpmRootDependencies(parents []yamlKey) bool {
+	section := func(name string) bool {
+		return name == "dependencies" || name == "devDependencies" || name == "optionalDependencies"
+	}
+	switch len(parents) {
+	case 1:
+		return section(parents[0].name)
+	case 3:
+		return parents[0].name == "importers" && parents[1].name == "." && section(parents[2].name)
+	}
+	return false
+}
+
+// cleanPnpmVersion drops the peer dependency suffix, e.g. "9.1.0(jiti@2.4.2)".
+func cleanPnpmVersion(value string) string {
+	value = strings.Trim(value, `'"`)
+	if i := strings.Index(value, "("); i != -1 {
+		value = value[:i]
+	}
+	if i := strings.Index(value, "_"); i != -1 {
+		value = value[:i]
+	}
+	return value
+}
+
+// npmLockedVersion reads lockfileVersion 2 and 3 ("packages") as well as
+// version 1 ("dependencies").
+func npmLockedVersion(content, name string) string {
+	var lock struct {
+		Packages map[string]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+		Dependencies map[string]struct {
+			Version string `json:"version"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal([]byte(content), &lock); err != nil {
+		return ""
+	}
+	if p, ok := lock.Packages["node_modules/"+name]; ok {
+		return p.Version
+	}
+	return lock.Dependencies[name].Version
+}
+
+// yarnLockedVersion reads entries such as `"eslint@^9.0.0", eslint@^9.1.0:`
+// followed by `version "9.1.0"` (classic) or `version: 9.1.0` (berry).
+func yarnLockedVersion(content, name string) string {
+	matched := false
+	for _, line := range strings.Split(content, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			matched = false
+			for _, descriptor := range strings.Split(strings.TrimSuffix(line, ":"), ",") {
+				descriptor = strings.Trim(strings.TrimSpace(descriptor), `"`)
+				if strings.HasPrefix(descriptor, name+"@") {
+					matched = true
+					break
+				}
+			}
+			continue
+		}
+		if !matched {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if v, ok := strings.CutPrefix(trimmed, "version"); ok {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(v, ":")), `"`)
+		}
+	}
+	return ""
+}
+
+// bunLockedVersion reads the "packages" entries of bun.lock, which look like
+// `"eslint": ["eslint@9.1.0", ...]`.
+func bunLockedVersion(content, name string) string {
+	pattern := regexp.MustCompile(`"` + regexp.QuoteMeta(name) + `":\s*\["` + regexp.QuoteMeta(name) + `@([^"]+)"`)
+	if match := pattern.FindStringSubmatch(content); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// PythonLockedVersion returns the version of a package pinned in uv.lock or
+// poetry.lock, which share the [[package]] name/version layout.
+func PythonLockedVersion(name string) (version string, lockfile string) {
+	for _, file := range []string{"uv.lock", "poetry.lock"} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		matched := false
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "[[package]]" {
+				matched = false
+			} else if line == `name = "`+name+`"` {
+				matched = true
+			} else if v, ok := strings.CutPrefix(line, "version = "); ok && matched {
+				return strings.Trim(v, `"`), file
+			}
+		}
+	}
+	return "", ""
+}
+
+// PythonDeclaredVersion returns the requirement of a package declared in
+// requirements.txt, requirements-dev.txt or pyproject.toml, e.g. ">=24.9.0".
+func PythonDeclaredVersion(name string) string {
+	pattern := regexp.MustCompile(`(?im)^\s*["']?` + regexp.QuoteMeta(name) + `(?:\[[^\]]*\])?\s*(?:=\s*["'])?([<>=!~^*][^"',#;\s]*)?["']?\s*(?:[,#;]|$)`)
+	for _, file := range []string{"requirements.txt", "requirements-dev.txt", "pyproject.toml"} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		if match := pattern.FindStringSubmatch(string(data)); match != nil {
+			if version := strings.TrimSpace(match[1]); version != "" {
+				return version
+			}
+			return "*"
+		}
+	}
+	return ""
+}
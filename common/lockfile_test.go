@@ -0,0 +1,111 @@
+package common
+
+import "testing"
+
+const pnpmLockV5 = `lockfileVersion: 5.4
+
+specifiers:
+  '@eslint/js': ^8.0.0
+  eslint: ^8.0.0
+  prettier: 3.0.0
+
+devDependencies:
+  '@eslint/js': 8.57.0
+  eslint: 8.57.0
+  prettier: 3.0.0
+
+packages:
+
+  /eslint/8.57.0:
+    resolution: {integrity: sha512-x}
+`
+
+const pnpmLockV5Workspace = `lockfileVersion: 5.4
+
+importers:
+
+  .:
+    specifiers:
+      eslint: ^8.0.0
+    devDependencies:
+      eslint: 8.56.0_typescript@5.4.5
+
+  packages/app:
+    specifiers:
+      eslint: ^7.0.0
+    devDependencies:
+      eslint: 7.32.0
+`
+
+const pnpmLockV6 = `lockfileVersion: '6.0'
+
+settings:
+  autoInstallPeers: true
+
+devDependencies:
+  '@eslint/js':
+    specifier: ^9.0.0
+    version: 9.1.1
+  eslint:
+    specifier: ^9.0.0
+    version: 9.1.0
+
+packages:
+
+  /eslint@9.1.0:
+    resolution: {integrity: sha512-x}
+`
+
+const pnpmLockV9 = `lockfileVersion: '9.0'
+
+importers:
+
+  .:
+    dependencies:
+      vitest:
+        specifier: ^2.0.0
+        version: 2.1.8(@types/node@22.10.2)
+    devDependencies:
+      eslint:
+        specifier: ^9.0.0
+        version: 9.17.0(jiti@2.4.2)
+
+  packages/app:
+    devDependencies:
+      eslint:
+        specifier: ^8.0.0
+        version: 8.57.0
+
+packages:
+
+  eslint@9.17.0:
+    resolution: {integrity: sha512-x}
+
+snapshots:
+
+  prettier@3.4.2: {}
+`
+
+func TestPnpmLockedVersion(t *testing.T) {
+	tests := []struct {
+		name, lockfile, pkg, want string
+	}{
+		{"v5 skips specifiers", pnpmLockV5, "eslint", "8.57.0"},
+		{"v5 scoped", pnpmLockV5, "@eslint/js", "8.57.0"},
+		{"v5 exact", pnpmLockV5, "prettier", "3.0.0"},
+		{"v5 workspace root", pnpmLockV5Workspace, "eslint", "8.56.0"},
+		{"v6", pnpmLockV6, "eslint", "9.1.0"},
+		{"v6 scoped", pnpmLockV6, "@eslint/js", "9.1.1"},
+		{"v9 importer", pnpmLockV9, "eslint", "9.17.0"},
+		{"v9 dependencies", pnpmLockV9, "vitest", "2.1.8"},
+		{"v9 not in the root importer", pnpmLockV9, "prettier", ""},
+		{"missing", pnpmLockV6, "husky", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pnpmLockedVersion(tt.lockfile, tt.pkg); got != tt.want {
+				t.Errorf("pnpmLockedVersion(%s) = %q, want %q", tt.pkg, got, tt.want)
+			}
+		})
+	}
+}
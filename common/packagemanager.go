@@ -0,0 +1,182 @@
+package common
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// PackageManager describes how to drive one of the supported node package managers.
+type PackageManager struct {
+	Name    string
+	RunCmd  string   // Command prefix for running scripts (e.g., "pnpm run", "npm run")
+	ExecCmd []string // Command prefix for running package binaries (e.g., "pnpm exec", "npx")
+	Offline bool     // Install from the local store or cache only, never from the network
+}
+
+// PackageManagers lists the supported package managers in lookup order.
+var PackageManagers = []PackageManager{
+	{Name: "pnpm", RunCmd: "pnpm run", ExecCmd: []string{"pnpm", "exec"}},
+	{Name: "npm", RunCmd: "npm run", ExecCmd: []string{"npx"}},
+	{Name: "yarn", RunCmd: "yarn run", ExecCmd: []string{"yarn"}},
+	{Name: "bun", RunCmd: "bun run", ExecCmd: []string{"bunx"}},
+}
+
+// lockfiles maps lockfiles to the package manager that writes them.
+var lockfiles = []struct {
+	file string
+	name string
+}{
+	{"pnpm-lock.yaml", "pnpm"},
+	{"pnpm-workspace.yaml", "pnpm"},
+	{"package-lock.json", "npm"},
+	{"yarn.lock", "yarn"},
+	{"bun.lockb", "bun"},
+	{"bun.lock", "bun"},
+}
+
+// DetectPackageManager picks the package manager of the project: the one that
+// owns the lockfile when it is installed, otherwise the first installed one.
+func DetectPackageManager() (PackageManager, bool) {
+	for _, lock := range lockfiles {
+		if _, err := os.Stat(lock.file); err != nil {
+			continue
+		}
+		if _, err := exec.LookPath(lock.name); err != nil {
+			continue
+		}
+		for _, pm := range PackageManagers {
+			if pm.Name == lock.name {
+				return pm, true
+			}
+		}
+	}
+
+	for _, pm := range PackageManagers {
+		if _, err := exec.LookPath(pm.Name); err == nil {
+			return pm, true
+		}
+	}
+	return PackageManager{}, false
+}
+
+// YarnClassic reports whether the installed yarn is 1.x, which needs -W to add
+// packages to a workspace root and has no 'yarn workspaces foreach'.
+func YarnClassic() bool {
+	out, err := exec.Command("yarn", "--version").Output()
+	return err == nil && strings.HasPrefix(strings.TrimSpace(string(out)), "1.")
+}
+
+// AddDevArgs builds the command that adds dev dependencies. A nil pkg targets
+// the project, which is the workspace root inside a monorepo; otherwise the
+// packages are added to that workspace package.
+func (pm PackageManager) AddDevArgs(packages []string, exact bool, pkg *WorkspacePackage) []string {
+	var args []string
+	switch pm.Name {
+	case "pnpm":
+		args = []string{"pnpm", "add", "--save-dev"}
+		if exact {
+			args = append(args, "--save-exact")
+		}
+		if pkg != nil {
+			args = append(args, "--filter", pkg.Name)
+		} else if _, err := os.Stat("pnpm-workspace.yaml"); err == nil {
+			args = append(args, "--workspace-root")
+		}
+	case "npm":
+		args = []string{"npm", "install", "--save-dev"}
+		if exact {
+			args = append(args, "--save-exact")
+		}
+		if pkg != nil {
+			args = append(args, "--workspace", pkg.Dir)
+		}
+	case "yarn":
+		if pkg != nil {
+			args = []string{"yarn", "workspace", pkg.Name, "add", "--dev"}
+		} else {
+			args = []string{"yarn", "add", "--dev"}
+			if len(WorkspacePatterns()) > 0 && YarnClassic() {
+				args = append(args, "--ignore-workspace-root-check")
+			}
+		}
+		if exact {
+			args = append(args, "--exact")
+		}
+	case "bun":
+		args = []string{"bun", "add", "--dev"}
+		if exact {
+			args = append(args, "--exact")
+		}
+		if pkg != nil {
+			args = append(args, "--cwd", pkg.Dir)
+		}
+	}
+	args = append(args, pm.offlineArgs()...)
+	return append(args, packages...)
+}
+
+// offlineArgs returns the flags that keep the package manager off the network.
+// Yarn 2+ has no such flag; it reads YARN_ENABLE_NETWORK=0 instead.
+func (pm PackageManager) offlineArgs() []string {
+	if !pm.Offline {
+		return nil
+	}
+	switch pm.Name {
+	case "pnpm", "npm":
+		return []string{"--offline"}
+	case "yarn":
+		if YarnClassic() {
+			return []string{"--offline"}
+		}
+	case "bun":
+		return []string{"--no-network"}
+	}
+	return nil
+}
+
+// RemoveArgs builds the command that uninstalls packages, the counterpart of
+// AddDevArgs.
+func (pm PackageManager) RemoveArgs(packages []string, pkg *WorkspacePackage) []string {
+	var args []string
+	switch pm.Name {
+	case "pnpm":
+		args = []string{"pnpm", "remove"}
+		if pkg != nil {
+			args = append(args, "--filter", pkg.Name)
+		} else if _, err := os.Stat("pnpm-workspace.yaml"); err == nil {
+			args = append(args, "--workspace-root")
+		}
+	case "npm":
+		args = []string{"npm", "uninstall"}
+		if pkg != nil {
+			args = append(args, "--workspace", pkg.Dir)
+		}
+	case "yarn":
+		if pkg != nil {
+			args = []string{"yarn", "workspace", pkg.Name, "remove"}
+		} else {
+			args = []string{"yarn", "remove"}
+			if len(WorkspacePatterns()) > 0 && YarnClassic() {
+				args = append(args, "--ignore-workspace-root-check")
+			}
+		}
+	case "bun":
+		args = []string{"bun", "remove"}
+		if pkg != nil {
+			args = append(args, "--cwd", pkg.Dir)
+		}
+	}
+	args = append(args, pm.offlineArgs()...)
+	return append(args, packages...)
+}
+
+// ExecArgs builds the command that runs a package binary. Offline, npx must
+// not download a missing binary.
+func (pm PackageManager) ExecArgs(args ...string) []string {
+	execArgs := append([]string{}, pm.ExecCmd...)
+	if pm.Offline && pm.Name == "npm" {
+		execArgs = append(execArgs, "--offline")
+	}
+	return append(execArgs, args...)
+}
@@ -0,0 +1,312 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/CrossEvol/setup/assets"
+)
+
+// VersionManifest maps each tool set (named after its setup command) to the
+// version ranges of its packages that the generated configs are tested with.
+type VersionManifest map[string]map[string]string
+
+// DefaultVersionManifest returns the embedded version manifest.
+func DefaultVersionManifest() VersionManifest {
+	var manifest VersionManifest
+	if err := json.Unmarshal(assets.Versions, &manifest); err != nil {
+		// The manifest is embedded at build time, so this is a programming error
+		panic(fmt.Sprintf("invalid version manifest: %v", err))
+	}
+	return manifest
+}
+
+// Range returns the tested range of a package, whichever tool set lists it.
+func (m VersionManifest) Range(name string) (string, bool) {
+	sets := make([]string, 0, len(m))
+	for set := range m {
+		sets = append(sets, set)
+	}
+	sort.Strings(sets)
+	for _, set := range sets {
+		if rng, ok := m[set][name]; ok {
+			return rng, true
+		}
+	}
+	return "", false
+}
+
+// ToolSet returns the tool set a package belongs to, preferring the one named
+// after the package itself.
+func (m VersionManifest) ToolSet(name string) string {
+	if _, ok := m[name][name]; ok {
+		return name
+	}
+	sets := make([]string, 0, len(m))
+	for set := range m {
+		sets = append(sets, set)
+	}
+	sort.Strings(sets)
+	for _, set := range sets {
+		if _, ok := m[set][name]; ok {
+			return set
+		}
+	}
+	return ""
+}
+
+// PinVersions appends the tested range to each package ("eslint@^9.9.0").
+// Packages missing from the manifest are left as they are.
+func (m VersionManifest) PinVersions(packages []string) []string {
+	pinned := make([]string, 0, len(packages))
+	for _, name := range packages {
+		if rng, ok := m.Range(name); ok {
+			name = name + "@" + rng
+		}
+		pinned = append(pinned, name)
+	}
+	return pinned
+}
+
+// semver is a parsed version without its prerelease, which prerelease returns
+// apart; build metadata is ignored.
+type semver [3]int
+
+func parseSemver(version string) (semver, int, bool) {
+	var v semver
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+"); i != -1 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) > 3 {
+		return v, 0, false
+	}
+	// Missing or wildcard parts ("9", "9.x") are reported through the count
+	count := 0
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return v, 0, false
+		}
+		v[i] = n
+		count++
+	}
+	return v, count, true
+}
+
+// prerelease returns the prerelease of a version, e.g. "rc.1" for "9.0.0-rc.1".
+func prerelease(version string) string {
+	version = strings.TrimSpace(version)
+	if i := strings.Index(version, "+"); i != -1 {
+		version = version[:i]
+	}
+	_, pre, _ := strings.Cut(version, "-")
+	return pre
+}
+
+// comparePrerelease orders prereleases of the same version: a release ("")
+// comes after its prereleases, numeric identifiers compare as numbers and
+// before alphanumeric ones.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil && an != bn:
+			if an < bn {
+				return -1
+			}
+			return 1
+		case aErr == nil && bErr != nil:
+			return -1
+		case aErr != nil && bErr == nil:
+			return 1
+		case aErr != nil && as[i] != bs[i]:
+			return strings.Compare(as[i], bs[i])
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
+
+func (v semver) compare(o semver) int {
+	for i := range v {
+		if v[i] != o[i] {
+			if v[i] < o[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// bounds turns a comparator into the interval of versions it allows. A zero
+// high means unbounded.
+func bounds(comparator string) (low, high semver, lowInclusive, highInclusive bool, ok bool) {
+	op := ""
+	for _, prefix := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(comparator, prefix) {
+			op = prefix
+			comparator = strings.TrimSpace(strings.TrimPrefix(comparator, prefix))
+			break
+		}
+	}
+	if comparator == "" || comparator == "*" || comparator == "x" {
+		return low, high, true, false, op == ""
+	}
+	v, count, parsed := parseSemver(comparator)
+	if !parsed {
+		return low, high, false, false, false
+	}
+	if count == 0 {
+		return low, high, true, false, true
+	}
+
+	// The next version outside a partial one: "9" -> 10.0.0, "9.1" -> 9.2.0
+	next := func(level int) semver {
+		n := v
+		n[level]++
+		for i := level + 1; i < 3; i++ {
+			n[i] = 0
+		}
+		return n
+	}
+
+	switch op {
+	case ">=":
+		return v, high, true, false, true
+	case ">":
+		if count < 3 {
+			return next(count - 1), high, true, false, true
+		}
+		return v, high, false, false, true
+	case "<":
+		return low, v, true, false, true
+	case "<=":
+		if count < 3 {
+			return low, next(count - 1), true, false, true
+		}
+		return low, v, true, true, true
+	case "^":
+		// The first non-zero part is the one that may not change
+		level := 0
+		for level < count-1 && v[level] == 0 {
+			level++
+		}
+		return v, next(level), true, false, true
+	case "~":
+		level := 1
+		if count < 2 {
+			level = 0
+		}
+		return v, next(level), true, false, true
+	default:
+		if count < 3 {
+			return v, next(count - 1), true, false, true
+		}
+		return v, v, true, true, true
+	}
+}
+
+// SatisfiesRange reports whether a version matches an npm range such as
+// "^9.9.0", "~2.1", ">=18 <21", "8.x || 9.x" or "1.2.3 - 1.4.0". As with npm,
+// a prerelease only matches a range naming a prerelease of the same version.
+func SatisfiesRange(version, rng string) bool {
+	v, count, ok := parseSemver(version)
+	if !ok || count != 3 {
+		return false
+	}
+	pre := prerelease(version)
+	// compare orders the version against a bound carrying a prerelease
+	compare := func(bound semver, boundPre string) int {
+		if c := v.compare(bound); c != 0 {
+			return c
+		}
+		return comparePrerelease(pre, boundPre)
+	}
+
+	for _, set := range strings.Split(rng, "||") {
+		set = strings.TrimSpace(set)
+		if from, to, hyphen := strings.Cut(set, " - "); hyphen {
+			set = ">=" + strings.TrimSpace(from) + " <=" + strings.TrimSpace(to)
+		}
+
+		matched, prereleaseAllowed := true, pre == ""
+		for _, comparator := range strings.Fields(set) {
+			low, high, lowInclusive, highInclusive, ok := bounds(comparator)
+			if !ok {
+				matched = false
+				break
+			}
+			// A bound is the comparator's own version when it carries its prerelease
+			own := strings.TrimLeft(comparator, "<>=^~v")
+			ownVersion, _, _ := parseSemver(own)
+			ownPre := prerelease(own)
+			lowPre, highPre := "", ""
+			if ownPre != "" {
+				if ownVersion == v {
+					prereleaseAllowed = true
+				}
+				if low == ownVersion {
+					lowPre = ownPre
+				}
+				if high == ownVersion {
+					highPre = ownPre
+				}
+			}
+
+			if c := compare(low, lowPre); c < 0 || (c == 0 && !lowInclusive) {
+				matched = false
+				break
+			}
+			if high != (semver{}) {
+				if c := compare(high, highPre); c > 0 || (c == 0 && !highInclusive) {
+					matched = false
+					break
+				}
+			}
+		}
+		if matched && prereleaseAllowed {
+			return true
+		}
+	}
+	return false
+}
+
+// MinVersion returns the lowest version a simple range allows, e.g. "9.9.0"
+// for "^9.9.0", or "" when it cannot be told.
+func MinVersion(rng string) string {
+	rng = strings.TrimSpace(strings.Split(rng, "||")[0])
+	fields := strings.Fields(rng)
+	if len(fields) == 0 {
+		return ""
+	}
+	comparator := strings.TrimLeft(fields[0], "^~>=v")
+	v, count, ok := parseSemver(comparator)
+	if !ok || count == 0 || strings.HasPrefix(fields[0], ">") && !strings.HasPrefix(fields[0], ">=") {
+		return ""
+	}
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
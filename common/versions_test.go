@@ -0,0 +1,105 @@
+package common
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSatisfiesRange(t *testing.T) {
+	tests := []struct {
+		version, rng string
+		want         bool
+	}{
+		{"9.9.0", "^9.9.0", true},
+		{"9.17.1", "^9.9.0", true},
+		{"9.8.9", "^9.9.0", false},
+		{"10.0.0", "^9.9.0", false},
+		{"0.6.9", "^0.6.4", true},
+		{"0.7.0", "^0.6.4", false},
+		{"0.0.3", "^0.0.3", true},
+		{"0.0.4", "^0.0.3", false},
+		{"2.1.9", "~2.1.0", true},
+		{"2.2.0", "~2.1.0", false},
+		{"2.9.0", "~2", true},
+		{"3.0.0", "~2", false},
+		{"18.0.0", ">=18 <21", true},
+		{"20.99.1", ">=18 <21", true},
+		{"21.0.0", ">=18 <21", false},
+		{"17.9.9", ">=18 <21", false},
+		{"9.1.0", ">9", false},
+		{"10.0.0", ">9", true},
+		{"9.1.0", "<=9.1", true},
+		{"9.2.0", "<=9.1", false},
+		{"8.57.0", "8.x || 9.x", true},
+		{"7.32.0", "8.x || 9.x", false},
+		{"1.3.7", "1.2.3 - 1.4.0", true},
+		{"1.4.1", "1.2.3 - 1.4.0", false},
+		{"3.3.3", "3.3.3", true},
+		{"3.3.4", "3.3.3", false},
+		{"1.0.0", "*", true},
+		{"v9.9.0", "^9.9.0", true},
+		{"9.9.0+build.5", "^9.9.0", true},
+
+		// A prerelease is below its release and only matches a range naming a
+		// prerelease of the same version
+		{"9.9.0-rc.1", "^9.9.0", false},
+		{"10.0.0-rc.1", "^9.9.0", false},
+		{"9.0.0-rc.1", "<9.0.0", false},
+		{"9.10.0-beta.0", ">=9.9.0", false},
+		{"9.9.0-rc.2", "^9.9.0-rc.1", true},
+		{"9.9.0-rc.0", "^9.9.0-rc.1", false},
+		{"9.9.0-rc.10", ">=9.9.0-rc.9", true},
+		{"9.9.0-beta", ">=9.9.0-alpha.1", true},
+		{"9.9.0-alpha", ">=9.9.0-alpha.1", false},
+		{"9.9.0", "^9.9.0-rc.1", true},
+		{"9.9.1", "^9.9.0-rc.1", true},
+
+		// Invalid versions and ranges match nothing
+		{"9.9", "^9.9.0", false},
+		{"nine", "^9.9.0", false},
+		{"", "^9.9.0", false},
+		{"9.9.0", "^nine", false},
+		{"9.9.0", ">=9 <ten", false},
+		{"9.9.0", "latest", false},
+	}
+	for _, tt := range tests {
+		if got := SatisfiesRange(tt.version, tt.rng); got != tt.want {
+			t.Errorf("SatisfiesRange(%q, %q) = %v, want %v", tt.version, tt.rng, got, tt.want)
+		}
+	}
+}
+
+func TestMinVersion(t *testing.T) {
+	tests := []struct {
+		rng, want string
+	}{
+		{"^9.9.0", "9.9.0"},
+		{"~2.1", "2.1.0"},
+		{">=18 <21", "18.0.0"},
+		{"8.x || 9.x", "8.0.0"},
+		{">9", ""},
+		{"*", ""},
+		{"latest", ""},
+	}
+	for _, tt := range tests {
+		if got := MinVersion(tt.rng); got != tt.want {
+			t.Errorf("MinVersion(%q) = %q, want %q", tt.rng, got, tt.want)
+		}
+	}
+}
+
+func TestVersionManifestRangesParse(t *testing.T) {
+	for set, packages := range DefaultVersionManifest() {
+		for name, rng := range packages {
+			for _, comparator := range strings.Fields(strings.ReplaceAll(rng, "||", " ")) {
+				if _, _, _, _, ok := bounds(comparator); !ok {
+					t.Errorf("%s: %s@%s: invalid comparator %q", set, name, rng, comparator)
+				}
+			}
+			lowest := MinVersion(rng)
+			if lowest == "" || !SatisfiesRange(lowest, rng) {
+				t.Errorf("%s: %s@%s: lowest version %q does not satisfy the range", set, name, rng, lowest)
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package common
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WorkspacePackage is a package found inside a workspace (monorepo).
+type WorkspacePackage struct {
+	Name    string
+	Dir     string
+	Private bool
+}
+
+// WorkspacePatterns returns the package globs declared in pnpm-workspace.yaml
+// or in the "workspaces" field of package.json (npm, yarn and bun).
+func WorkspacePatterns() []string {
+	if data, err := os.ReadFile("pnpm-workspace.yaml"); err == nil {
+		return parsePnpmWorkspace(string(data))
+	}
+
+	data, err := os.ReadFile("package.json")
+	if err != nil {
+		return nil
+	}
+	var pkgJSON map[string]interface{}
+	if err := json.Unmarshal(data, &pkgJSON); err != nil {
+		return nil
+	}
+
+	var patterns []string
+	switch workspaces := pkgJSON["workspaces"].(type) {
+	case []interface{}:
+		for _, w := range workspaces {
+			if s, ok := w.(string); ok {
+				patterns = append(patterns, s)
+			}
+		}
+	case map[string]interface{}:
+		// Yarn classic allows { "packages": [...], "nohoist": [...] }
+		if list, ok := workspaces["packages"].([]interface{}); ok {
+			for _, w := range list {
+				if s, ok := w.(string); ok {
+					patterns = append(patterns, s)
+				}
+			}
+		}
+	}
+	return patterns
+}
+
+// parsePnpmWorkspace extracts the entries of the "packages" list. Only the
+// block sequence form written by pnpm itself is supported.
+func parsePnpmWorkspace(content string) []string {
+	var patterns []string
+	inPackages := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") && !strings.HasPrefix(trimmed, "-") {
+			inPackages = strings.HasPrefix(trimmed, "packages:")
+			continue
+		}
+		if inPackages && strings.HasPrefix(trimmed, "-") {
+			value := strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
+			if i := strings.Index(value, " #"); i != -1 {
+				value = strings.TrimSpace(value[:i])
+			}
+			patterns = append(patterns, strings.Trim(value, `'"`))
+		}
+	}
+	return patterns
+}
+
+// WorkspacePackages expands the workspace patterns into the packages they
+// contain, sorted by name. Negated patterns ("!packages/internal") exclude
+// directories.
+func WorkspacePackages() []WorkspacePackage {
+	excluded := make(map[string]bool)
+	var includes []string
+	for _, pattern := range WorkspacePatterns() {
+		if strings.HasPrefix(pattern, "!") {
+			matches, _ := filepath.Glob(strings.TrimPrefix(pattern, "!"))
+			for _, m := range matches {
+				excluded[filepath.Clean(m)] = true
+			}
+			continue
+		}
+		includes = append(includes, pattern)
+	}
+
+	seen := make(map[string]bool)
+	var packages []WorkspacePackage
+	for _, pattern := range includes {
+		// "packages/**" is treated like "packages/*", which covers the common layouts
+		pattern = strings.ReplaceAll(pattern, "**", "*")
+		matches, _ := filepath.Glob(pattern)
+		for _, dir := range matches {
+			dir = filepath.Clean(dir)
+			if excluded[dir] || seen[dir] {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+			if err != nil {
+				continue
+			}
+			var pkgJSON map[string]interface{}
+			if err := json.Unmarshal(data, &pkgJSON); err != nil {
+				continue
+			}
+			seen[dir] = true
+			name, _ := pkgJSON["name"].(string)
+			if name == "" {
+				name = filepath.Base(dir)
+			}
+			private, _ := pkgJSON["private"].(bool)
+			packages = append(packages, WorkspacePackage{Name: name, Dir: filepath.ToSlash(dir), Private: private})
+		}
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+	return packages
+}
+
+// IsWorkspaceRoot reports whether the current directory is the root of a
+// pnpm, yarn, npm or bun workspace, or of an Nx or Turborepo monorepo.
+func IsWorkspaceRoot() bool {
+	return len(WorkspacePatterns()) > 0 || WorkspaceRunner() != ""
+}
+
+// WorkspaceRunner returns "nx" or "turbo" when the monorepo uses one of them
+// to run tasks, or "" otherwise.
+func WorkspaceRunner() string {
+	if _, err := os.Stat("nx.json"); err == nil {
+		return "nx"
+	}
+	if _, err := os.Stat("turbo.json"); err == nil {
+		return "turbo"
+	}
+	return ""
+}
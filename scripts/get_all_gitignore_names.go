@@ -1,79 +1,79 @@
 package main
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"os"
-	"strings"
-)
-
-func (r *Result) Marshal() ([]byte, error) {
-	return json.Marshal(r)
-}
+	"time"
 
-type Result struct {
-	SHA       string `json:"sha"`
-	URL       string `json:"url"`
-	Tree      []Tree `json:"tree"`
-	Truncated bool   `json:"truncated"`
-}
+	"github.com/CrossEvol/setup/common"
+)
 
-type Tree struct {
-	Path string `json:"path"`
-	Mode string `json:"mode"`
-	Type string `json:"type"`
-	SHA  string `json:"sha"`
-	URL  string `json:"url"`
-}
+// The paths of the embedded assets, relative to the repository root.
+const (
+	pairsFile  = "assets/gitignore_pairs.json"
+	bundleFile = "assets/gitignore_bundle.json.gz"
+)
 
+// main regenerates the embedded template index and bundle. Users refresh the
+// index with 'setup ignore sync' instead; GITHUB_TOKEN is honoured by both.
 func main() {
-	// Fetch data from the API
-	resp, err := http.Get("https://api.github.com/repos/github/gitignore/git/trees/main?recursive=1")
+	gitignoreMap, err := common.NewGitignoreIndex().Fetch()
 	if err != nil {
-		fmt.Println("Error fetching data:", err)
-		return
+		fmt.Println("Error listing the templates:", err)
+		os.Exit(1)
 	}
-	defer resp.Body.Close()
 
-	// Read the response body
-	body, err := ioutil.ReadAll(resp.Body)
+	// Marshal the map to JSON
+	jsonData, err := json.MarshalIndent(gitignoreMap, "", "  ")
 	if err != nil {
-		fmt.Println("Error reading response body:", err)
-		return
+		fmt.Println("Error marshalling map to JSON:", err)
+		os.Exit(1)
 	}
 
-	// Unmarshal the JSON data
-	var result Result
-	err = json.Unmarshal(body, &result)
+	// Write JSON data to a file
+	err = os.WriteFile(pairsFile, jsonData, 0644)
 	if err != nil {
-		fmt.Println("Error unmarshalling JSON:", err)
-		return
+		fmt.Println("Error writing JSON to file:", err)
+		os.Exit(1)
 	}
 
-	// Collect paths with .gitignore suffix
-	gitignoreMap := make(map[string]string)
-	for _, tree := range result.Tree {
-		if strings.HasSuffix(tree.Path, ".gitignore") {
-			key := strings.TrimSuffix(tree.Path, ".gitignore")
-			gitignoreMap[key] = tree.Path
+	fmt.Printf("%d gitignore paths have been written to %s\n", len(gitignoreMap), pairsFile)
+
+	// Fetch the content of every template into the embedded bundle
+	fetcher := &common.GitignoreFetcher{
+		BaseURL: common.DefaultGitignoreBaseURL,
+		Client:  &http.Client{Timeout: 30 * time.Second},
+	}
+	bundle := make(map[string]string)
+	for _, path := range gitignoreMap {
+		content, err := fetcher.Fetch(path, true)
+		if err != nil {
+			fmt.Printf("Error fetching %s: %v\n", path, err)
+			os.Exit(1)
 		}
+		bundle[path] = string(content)
 	}
 
-	// Marshal the map to JSON
-	jsonData, err := json.MarshalIndent(gitignoreMap, "", "  ")
-	if err != nil {
-		fmt.Println("Error marshalling map to JSON:", err)
-		return
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(writer).Encode(bundle); err != nil {
+		fmt.Println("Error marshalling the bundle:", err)
+		os.Exit(1)
+	}
+	if err := writer.Close(); err != nil {
+		fmt.Println("Error compressing the bundle:", err)
+		os.Exit(1)
 	}
 
-	// Write JSON data to a file
-	err = os.WriteFile("assets/gitignore_pairs.json", jsonData, 0644)
+	err = os.WriteFile(bundleFile, buf.Bytes(), 0644)
 	if err != nil {
-		fmt.Println("Error writing JSON to file:", err)
-		return
+		fmt.Println("Error writing the bundle:", err)
+		os.Exit(1)
 	}
 
-	fmt.Println("gitignore paths have been written to gitignore_paths.json")
+	fmt.Printf("%d gitignore templates have been written to %s\n", len(bundle), bundleFile)
 }